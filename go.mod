@@ -1,6 +1,6 @@
 module github.com/mabu/algo
 
-go 1.23.3
+go 1.24
 
 require (
 	github.com/google/go-cmp v0.7.0
@@ -1,7 +1,11 @@
 // Package bfs implements breadth-first search algorithm.
 package bfs
 
-import "github.com/mabu/algo/graph"
+import (
+	"iter"
+
+	"github.com/mabu/algo/graph"
+)
 
 // Path returns one of the shortest paths, including both endpoints.
 // If the path does not exist, returns nil.
@@ -46,6 +50,175 @@ func Path(g graph.Graph, from, to int) []int {
 	return nil
 }
 
+// Walk returns an iterator over the nodes reachable from start in breadth-first order,
+// together with their distances from start.
+// May be more efficient if g implements graph.Sized.
+func Walk(g graph.Graph, start int) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		parent := newTree(g)
+		parent.set(start, start, 0)
+		queue := []int{start}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			d := parent.depth(v)
+			if !yield(v, d) {
+				return
+			}
+			for _, u := range g.Adjacent(v) {
+				if !parent.has(u) {
+					parent.set(u, v, d+1)
+					queue = append(queue, u)
+				}
+			}
+		}
+	}
+}
+
+// Tree holds the shortest paths from the sources of a search to all nodes reachable from them.
+type Tree struct {
+	parent tree
+}
+
+// Distances returns the shortest paths from the nearest of sources to all nodes reachable from them.
+// May be more efficient if g implements graph.Sized.
+func Distances(g graph.Graph, sources ...int) *Tree {
+	parent := newTree(g)
+	var queue []int
+	for _, s := range sources {
+		if !parent.has(s) {
+			parent.set(s, s, 0)
+			queue = append(queue, s)
+		}
+	}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, u := range g.Adjacent(v) {
+			if !parent.has(u) {
+				parent.set(u, v, parent.depth(v)+1)
+				queue = append(queue, u)
+			}
+		}
+	}
+	return &Tree{parent}
+}
+
+// Dist returns the number of edges on the shortest path from the nearest source to v, and true.
+// If v is not reachable, returns false.
+func (t *Tree) Dist(v int) (int, bool) {
+	if !t.parent.has(v) {
+		return 0, false
+	}
+	return t.parent.depth(v), true
+}
+
+// Parent returns the node before v on the shortest path from the nearest source to v, and true.
+// If v is a source or is not reachable, returns false.
+func (t *Tree) Parent(v int) (int, bool) {
+	if !t.parent.has(v) || t.parent.depth(v) == 0 {
+		return 0, false
+	}
+	return t.parent.get(v), true
+}
+
+// PathTo returns one of the shortest paths from the nearest source to v, including both endpoints.
+// If v is not reachable, returns nil.
+func (t *Tree) PathTo(v int) []int {
+	if !t.parent.has(v) {
+		return nil
+	}
+	path := make([]int, t.parent.depth(v)+1)
+	for i := len(path) - 1; i >= 0; i-- {
+		path[i] = v
+		v = t.parent.get(v)
+	}
+	return path
+}
+
+// tree keeps the parent and the depth of every node in a search tree.
+// Searches in weighted graphs use the depth for the distance.
+// Path does not need the depths, so it uses parent instead.
+type tree interface {
+	has(int) bool
+	get(int) int
+	depth(int) int
+	set(c, p, depth int)
+}
+
+// newTree returns a treeSlice if g implements graph.Sized, otherwise a treeMap.
+func newTree(g graph.Graph) tree {
+	if s, ok := g.(graph.Sized); ok {
+		return newTreeSlice(s.Size())
+	}
+	return make(treeMap[int])
+}
+
+// treeMap keeps the parents of nodes of any comparable type.
+// treeMap[int] implements tree.
+type treeMap[S comparable] map[S]struct {
+	p     S
+	depth int
+}
+
+func (m treeMap[S]) has(v S) bool {
+	_, ok := m[v]
+	return ok
+}
+
+func (m treeMap[S]) get(v S) S {
+	return m[v].p
+}
+
+func (m treeMap[S]) depth(v S) int {
+	return m[v].depth
+}
+
+func (m treeMap[S]) set(c, p S, depth int) {
+	m[c] = struct {
+		p     S
+		depth int
+	}{p, depth}
+}
+
+// path returns the path from the root of the tree to v.
+func (m treeMap[S]) path(v S) []S {
+	path := make([]S, m.depth(v)+1)
+	for i := len(path) - 1; i >= 0; i-- {
+		path[i] = v
+		v = m.get(v)
+	}
+	return path
+}
+
+type treeSlice struct {
+	isSet []bool
+	p     []int
+	d     []int
+}
+
+func newTreeSlice(size int) treeSlice {
+	return treeSlice{make([]bool, size), make([]int, size), make([]int, size)}
+}
+
+func (s treeSlice) has(v int) bool {
+	return s.isSet[v]
+}
+
+func (s treeSlice) get(v int) int {
+	return s.p[v]
+}
+
+func (s treeSlice) depth(v int) int {
+	return s.d[v]
+}
+
+func (s treeSlice) set(c, p, depth int) {
+	s.p[c] = p
+	s.d[c] = depth
+	s.isSet[c] = true
+}
+
 type parent interface {
 	has(int) bool
 	get(int) int
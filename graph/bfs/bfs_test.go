@@ -79,3 +79,95 @@ func TestPath(t *testing.T) {
 		}
 	}
 }
+
+func TestDistances(t *testing.T) {
+	g := [][]int{
+		{1, 2},
+		{3},
+		{3},
+		{4},
+		{},
+		{4},
+		{6},
+	}
+	for _, tc := range []struct {
+		sources    []int
+		wantDist   []int // -1 for unreachable nodes.
+		wantParent []int // -1 for sources and unreachable nodes.
+		wantPaths  [][]int
+	}{
+		{
+			wantDist:   []int{-1, -1, -1, -1, -1, -1, -1},
+			wantParent: []int{-1, -1, -1, -1, -1, -1, -1},
+			wantPaths:  [][]int{nil, nil, nil, nil, nil, nil, nil},
+		},
+		{
+			sources:    []int{0},
+			wantDist:   []int{0, 1, 1, 2, 3, -1, -1},
+			wantParent: []int{-1, 0, 0, 1, 3, -1, -1},
+			wantPaths:  [][]int{{0}, {0, 1}, {0, 2}, {0, 1, 3}, {0, 1, 3, 4}, nil, nil},
+		},
+		{
+			sources:    []int{0, 5, 6, 0},
+			wantDist:   []int{0, 1, 1, 2, 1, 0, 0},
+			wantParent: []int{-1, 0, 0, 1, 5, -1, -1},
+			wantPaths:  [][]int{{0}, {0, 1}, {0, 2}, {0, 1, 3}, {5, 4}, {5}, {6}},
+		},
+	} {
+		for name, g := range map[string]graph.Graph{
+			"Unsized": unsized(g),
+			"Sized":   graph.AdjList(g),
+		} {
+			tree := Distances(g, tc.sources...)
+			for v := range tc.wantDist {
+				if got, ok := tree.Dist(v); ok != (tc.wantDist[v] >= 0) || ok && got != tc.wantDist[v] {
+					t.Errorf("%s Distances(g, %v).Dist(%d) = %d, %t, want %d", name, tc.sources, v, got, ok, tc.wantDist[v])
+				}
+				if got, ok := tree.Parent(v); ok != (tc.wantParent[v] >= 0) || ok && got != tc.wantParent[v] {
+					t.Errorf("%s Distances(g, %v).Parent(%d) = %d, %t, want %d", name, tc.sources, v, got, ok, tc.wantParent[v])
+				}
+				if got := tree.PathTo(v); !reflect.DeepEqual(got, tc.wantPaths[v]) {
+					t.Errorf("%s Distances(g, %v).PathTo(%d) = %v, want %v", name, tc.sources, v, got, tc.wantPaths[v])
+				}
+			}
+		}
+	}
+}
+
+func TestWalk(t *testing.T) {
+	g := [][]int{
+		{1, 2},
+		{3, 0},
+		{3},
+		{4, 1},
+		{},
+		{0},
+	}
+	type step struct{ v, depth int }
+	for _, tc := range []struct {
+		start int
+		limit int // Number of steps before breaking, or 0 to not break.
+		want  []step
+	}{
+		{start: 0, want: []step{{0, 0}, {1, 1}, {2, 1}, {3, 2}, {4, 3}}},
+		{start: 0, limit: 2, want: []step{{0, 0}, {1, 1}}},
+		{start: 4, want: []step{{4, 0}}},
+		{start: 5, want: []step{{5, 0}, {0, 1}, {1, 2}, {2, 2}, {3, 3}, {4, 4}}},
+	} {
+		for name, g := range map[string]graph.Graph{
+			"Unsized": unsized(g),
+			"Sized":   graph.AdjList(g),
+		} {
+			var got []step
+			for v, depth := range Walk(g, tc.start) {
+				got = append(got, step{v, depth})
+				if len(got) == tc.limit {
+					break
+				}
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("%s Walk(g, %d) with limit %d = %v, want %v", name, tc.start, tc.limit, got, tc.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package bfs
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/mabu/algo/graph"
+)
+
+// unsizedReversible hides the Size method of a graph.
+type unsizedReversible struct {
+	g *graph.ReversibleAdjList
+}
+
+func (g unsizedReversible) Adjacent(v int) []int     { return g.g.Adjacent(v) }
+func (g unsizedReversible) Predecessors(v int) []int { return g.g.Predecessors(v) }
+
+func randomGraph(r *rand.Rand, n, m int) graph.AdjList {
+	g := make(graph.AdjList, n)
+	for range m {
+		v := r.IntN(n)
+		g[v] = append(g[v], r.IntN(n))
+	}
+	return g
+}
+
+// isPath reports whether path is a path from from to to in g.
+func isPath(g graph.Graph, path []int, from, to int) bool {
+	if len(path) == 0 || path[0] != from || path[len(path)-1] != to {
+		return false
+	}
+	for i := 1; i < len(path); i++ {
+		if !slices.Contains(g.Adjacent(path[i-1]), path[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestBidirectionalPath(t *testing.T) {
+	for _, tc := range []struct {
+		g        graph.AdjList
+		from, to int
+		want     []int
+	}{
+		{g: graph.AdjList{{}}, want: []int{0}},
+		{g: graph.AdjList{{1}, {}}, from: 1, to: 0},
+		{g: graph.AdjList{{1}, {}}, from: 0, to: 1, want: []int{0, 1}},
+		{g: graph.AdjList{{1}, {2}, {3}, {0}}, from: 2, to: 1, want: []int{2, 3, 0, 1}},
+		{
+			g: graph.AdjList{
+				{1, 2},
+				{1, 3},
+				{4},
+				{1, 2, 4},
+				{1, 0, 4, 2, 3},
+			},
+			from: 0,
+			to:   4,
+			want: []int{0, 2, 4},
+		},
+	} {
+		rg := graph.NewReversible(tc.g)
+		for name, g := range map[string]graph.Reversible{
+			"Sized":   rg,
+			"Unsized": unsizedReversible{rg},
+		} {
+			if got := BidirectionalPath(g, tc.from, tc.to); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("%s BidirectionalPath(%v, %d, %d) = %v, want %v", name, tc.g, tc.from, tc.to, got, tc.want)
+			}
+		}
+	}
+}
+
+func TestBidirectionalPathRandom(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for i := range 500 {
+		n := 1 + r.IntN(50)
+		g := randomGraph(r, n, r.IntN(3*n))
+		rg := graph.NewReversible(g)
+		from, to := r.IntN(n), r.IntN(n)
+		want := Path(g, from, to)
+		for name, g := range map[string]graph.Reversible{
+			"Sized":   rg,
+			"Unsized": unsizedReversible{rg},
+		} {
+			got := BidirectionalPath(g, from, to)
+			if want == nil && got != nil || want != nil && (len(got) != len(want) || !isPath(g, got, from, to)) {
+				t.Errorf("Graph #%d, %s: BidirectionalPath(%v, %d, %d) = %v, want a path like %v", i, name, g, from, to, got, want)
+			}
+		}
+	}
+}
+
+func benchmarkPath(b *testing.B, path func(g *graph.ReversibleAdjList, from, to int) []int) {
+	const n = 1000000
+	r := rand.New(rand.NewPCG(1, 2))
+	g := graph.NewReversible(randomGraph(r, n, 5*n))
+	for b.Loop() {
+		path(g, r.IntN(n), r.IntN(n))
+	}
+}
+
+func BenchmarkPath(b *testing.B) {
+	benchmarkPath(b, func(g *graph.ReversibleAdjList, from, to int) []int { return Path(g, from, to) })
+}
+
+func BenchmarkBidirectionalPath(b *testing.B) {
+	benchmarkPath(b, func(g *graph.ReversibleAdjList, from, to int) []int { return BidirectionalPath(g, from, to) })
+}
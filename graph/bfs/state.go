@@ -0,0 +1,46 @@
+package bfs
+
+import "iter"
+
+// PathFunc returns one of the shortest paths from start to a state for which goal returns true,
+// including both endpoints, in a graph of states of any comparable type.
+// next returns the states that are adjacent to the given one.
+// If no goal state is reachable, returns nil.
+// If the number of reachable states is infinite, and none of them is a goal, it never returns;
+// use PathFuncLimit to avoid that.
+func PathFunc[S comparable](start S, next func(S) iter.Seq[S], goal func(S) bool) []S {
+	path, _ := PathFuncLimit(start, next, goal, -1)
+	return path
+}
+
+// PathFuncLimit is like PathFunc, but does not visit more than limit states, including start,
+// unless limit is negative.
+// complete reports whether the search was finished before reaching the limit:
+// if path is nil and complete is true, no goal state is reachable.
+func PathFuncLimit[S comparable](start S, next func(S) iter.Seq[S], goal func(S) bool, limit int) (path []S, complete bool) {
+	if goal(start) {
+		return []S{start}, true
+	}
+	parent := make(treeMap[S])
+	parent.set(start, start, 0)
+	queue := []S{start}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		d := parent.depth(v) + 1
+		for u := range next(v) {
+			if parent.has(u) {
+				continue
+			}
+			if limit >= 0 && len(parent) >= limit {
+				return nil, false
+			}
+			parent.set(u, v, d)
+			if goal(u) {
+				return parent.path(u), true
+			}
+			queue = append(queue, u)
+		}
+	}
+	return nil, true
+}
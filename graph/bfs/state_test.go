@@ -0,0 +1,79 @@
+package bfs
+
+import (
+	"iter"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// jugs is the state of the puzzle where water is poured between jugs of 3 and 5 liters.
+type jugs struct {
+	small, large int
+}
+
+func (j jugs) next() iter.Seq[jugs] {
+	pour := min(j.small, 5-j.large)
+	back := min(j.large, 3-j.small)
+	return slices.Values([]jugs{
+		{3, j.large},
+		{j.small, 5},
+		{0, j.large},
+		{j.small, 0},
+		{j.small - pour, j.large + pour},
+		{j.small + back, j.large - back},
+	})
+}
+
+func TestPathFunc(t *testing.T) {
+	for _, tc := range []struct {
+		desc  string
+		start jugs
+		goal  func(jugs) bool
+		want  []jugs
+	}{
+		{
+			desc:  "start is the goal",
+			start: jugs{1, 1},
+			goal:  func(j jugs) bool { return j.small == 1 },
+			want:  []jugs{{1, 1}},
+		},
+		{
+			desc: "measure 4 liters",
+			goal: func(j jugs) bool { return j.large == 4 },
+			want: []jugs{{0, 0}, {0, 5}, {3, 2}, {0, 2}, {2, 0}, {2, 5}, {3, 4}},
+		},
+		{
+			desc: "unreachable",
+			goal: func(j jugs) bool { return j.small+j.large > 8 },
+		},
+	} {
+		if got := PathFunc(tc.start, jugs.next, tc.goal); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: PathFunc(%v) = %v, want %v", tc.desc, tc.start, got, tc.want)
+		}
+	}
+}
+
+func TestPathFuncLimit(t *testing.T) {
+	// Strings that are made from "a" by appending "a" or "b", which is an infinite graph.
+	next := func(s string) iter.Seq[string] {
+		return slices.Values([]string{s + "a", s + "b"})
+	}
+	for _, tc := range []struct {
+		goal         string
+		limit        int
+		want         []string
+		wantComplete bool
+	}{
+		{goal: "abb", limit: -1, want: []string{"a", "ab", "abb"}, wantComplete: true},
+		{goal: "abb", limit: 7, want: []string{"a", "ab", "abb"}, wantComplete: true},
+		{goal: "abb", limit: 6},
+		{goal: "a", limit: 0, want: []string{"a"}, wantComplete: true},
+		{goal: "b", limit: 1000},
+	} {
+		got, complete := PathFuncLimit("a", next, func(s string) bool { return s == tc.goal }, tc.limit)
+		if !reflect.DeepEqual(got, tc.want) || complete != tc.wantComplete {
+			t.Errorf("PathFuncLimit(a, next, %q, %d) = %v, %t, want %v, %t", tc.goal, tc.limit, got, complete, tc.want, tc.wantComplete)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package bfs
+
+import (
+	"math"
+	"slices"
+
+	"github.com/mabu/algo/graph"
+)
+
+// ZeroOnePath returns one of the shortest paths in a graph whose edges have weights 0 or 1,
+// including both endpoints, and its length.
+// If the path does not exist, returns nil.
+// Takes O(n + m) time, using a deque instead of a priority queue:
+// nodes reached by edges with weight 0 go to the front, and the others to the back.
+// Panics if it finds an edge with a different weight.
+// May be more efficient if g implements graph.Sized.
+func ZeroOnePath[W graph.Number](g graph.Weighted[W], from, to int) ([]int, W) {
+	parent := newTree(g)
+	parent.set(from, from, 0)
+	var d deque[entry]
+	d.pushBack(entry{from, 0})
+	for d.len() > 0 {
+		e := d.popFront()
+		if parent.depth(e.v) != e.dist {
+			continue // The node was reached by a shorter path later.
+		}
+		if e.v == to {
+			return weightedPath(parent, from, to), W(e.dist)
+		}
+		weights := g.Weights(e.v)
+		for i, u := range g.Adjacent(e.v) {
+			w := weights[i]
+			if w != 0 && w != 1 {
+				panic("bfs: edge weight is not 0 or 1")
+			}
+			dist := e.dist + int(w)
+			if parent.has(u) && parent.depth(u) <= dist {
+				continue
+			}
+			parent.set(u, e.v, dist)
+			if w == 0 {
+				d.pushFront(entry{u, dist})
+			} else {
+				d.pushBack(entry{u, dist})
+			}
+		}
+	}
+	return nil, 0
+}
+
+// DialPath returns one of the shortest paths in a graph with small non-negative integer weights,
+// including both endpoints, and its length.
+// If the path does not exist, returns nil.
+// Dial's algorithm keeps the nodes in buckets by their distance, instead of a priority queue,
+// so it takes O(n + m + D) time, where D is the length of the path,
+// or if to is not reachable, the largest distance of a node reachable from from.
+// The buckets take O(C) memory, where C is the largest weight.
+// Panics if it finds an edge with a negative weight,
+// or a path whose length does not fit in an int.
+// May be more efficient if g implements graph.Sized.
+func DialPath[W graph.Integer](g graph.Weighted[W], from, to int) ([]int, W) {
+	parent := newTree(g)
+	parent.set(from, from, 0)
+	// The pending entries have distances in [dist, dist + C],
+	// so the bucket of an entry is its distance modulo len(buckets) > C.
+	buckets := make([][]entry, 2)
+	buckets[0] = []entry{{from, 0}}
+	for dist, pending := 0, 1; pending > 0; dist++ {
+		b := dist % len(buckets)
+		for len(buckets[b]) > 0 {
+			e := buckets[b][len(buckets[b])-1]
+			buckets[b] = buckets[b][:len(buckets[b])-1]
+			pending--
+			if parent.depth(e.v) != e.dist {
+				continue // The node was reached by a shorter path later.
+			}
+			if e.v == to {
+				return weightedPath(parent, from, to), W(e.dist)
+			}
+			weights := g.Weights(e.v)
+			for i, u := range g.Adjacent(e.v) {
+				w := weights[i]
+				if w < 0 {
+					panic("bfs: negative edge weight")
+				}
+				if W(int(w)) != w || int(w) < 0 || int(w) > math.MaxInt-dist {
+					panic("bfs: path length overflows int")
+				}
+				ud := dist + int(w)
+				if parent.has(u) && parent.depth(u) <= ud {
+					continue
+				}
+				parent.set(u, e.v, ud)
+				if int(w) >= len(buckets) {
+					buckets = regroup(buckets, int(w))
+					b = dist % len(buckets)
+				}
+				buckets[ud%len(buckets)] = append(buckets[ud%len(buckets)], entry{u, ud})
+				pending++
+			}
+		}
+	}
+	return nil, 0
+}
+
+// regroup returns more than maxWeight buckets with the same entries.
+func regroup(buckets [][]entry, maxWeight int) [][]entry {
+	n := len(buckets)
+	for n <= maxWeight {
+		n *= 2
+	}
+	res := make([][]entry, n)
+	for _, b := range buckets {
+		for _, e := range b {
+			res[e.dist%n] = append(res[e.dist%n], e)
+		}
+	}
+	return res
+}
+
+// entry is a node reached by a path of length dist.
+type entry struct {
+	v, dist int
+}
+
+// weightedPath returns the path from from to to by following the parents.
+// Unlike in a breadth-first search, the depth is not the number of nodes on the path.
+func weightedPath(parent tree, from, to int) []int {
+	path := []int{to}
+	for v := to; v != from; {
+		v = parent.get(v)
+		path = append(path, v)
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// deque is a double-ended queue in a ring buffer.
+type deque[T any] struct {
+	items      []T
+	head, size int
+}
+
+func (d *deque[T]) len() int {
+	return d.size
+}
+
+func (d *deque[T]) grow() {
+	if d.size < len(d.items) {
+		return
+	}
+	items := make([]T, max(4, 2*len(d.items)))
+	for i := range d.size {
+		items[i] = d.items[(d.head+i)%len(d.items)]
+	}
+	d.items, d.head = items, 0
+}
+
+func (d *deque[T]) pushFront(x T) {
+	d.grow()
+	d.head = (d.head + len(d.items) - 1) % len(d.items)
+	d.items[d.head] = x
+	d.size++
+}
+
+func (d *deque[T]) pushBack(x T) {
+	d.grow()
+	d.items[(d.head+d.size)%len(d.items)] = x
+	d.size++
+}
+
+func (d *deque[T]) popFront() T {
+	x := d.items[d.head]
+	d.head = (d.head + 1) % len(d.items)
+	d.size--
+	return x
+}
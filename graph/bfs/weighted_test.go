@@ -0,0 +1,150 @@
+package bfs
+
+import (
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+
+	"github.com/mabu/algo/graph"
+	"github.com/mabu/algo/graph/shortest"
+)
+
+// unsizedWeighted hides the Size method of a graph.
+type unsizedWeighted struct {
+	g *graph.WeightedAdjList[int]
+}
+
+func (g unsizedWeighted) Adjacent(v int) []int { return g.g.Adjacent(v) }
+func (g unsizedWeighted) Weights(v int) []int  { return g.g.Weights(v) }
+
+func randomWeightedGraph(r *rand.Rand, n, m, maxW int) *graph.WeightedAdjList[int] {
+	g := graph.NewWeightedAdjList[int](n)
+	for range m {
+		g.AddEdge(r.IntN(n), r.IntN(n), r.IntN(maxW+1))
+	}
+	return g
+}
+
+// pathLength returns the length of the path using the lightest edges between its consecutive nodes,
+// and false if some of the edges do not exist.
+func pathLength(g graph.Weighted[int], path []int) (int, bool) {
+	res := 0
+	for i := 1; i < len(path); i++ {
+		best := -1
+		for j, u := range g.Adjacent(path[i-1]) {
+			if w := g.Weights(path[i-1])[j]; u == path[i] && (best == -1 || w < best) {
+				best = w
+			}
+		}
+		if best == -1 {
+			return 0, false
+		}
+		res += best
+	}
+	return res, true
+}
+
+func TestWeightedPaths(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, tc := range []struct {
+		name string
+		maxW int
+		path func(g graph.Weighted[int], from, to int) ([]int, int)
+	}{
+		{"ZeroOnePath", 1, ZeroOnePath[int]},
+		{"DialPath", 1, DialPath[int]},
+		{"DialPath", 7, DialPath[int]},
+		{"DialPath", 100, DialPath[int]},
+	} {
+		for i := range 300 {
+			n := 1 + r.IntN(40)
+			g := randomWeightedGraph(r, n, r.IntN(4*n), tc.maxW)
+			from, to := r.IntN(n), r.IntN(n)
+			wantPath, want := shortest.Path[int](g, from, to)
+			for name, wg := range map[string]graph.Weighted[int]{
+				"Sized":   g,
+				"Unsized": unsizedWeighted{g},
+			} {
+				got, gotDist := tc.path(wg, from, to)
+				if wantPath == nil {
+					if got != nil {
+						t.Errorf("Graph #%d with weights up to %d, %s: %s(g, %d, %d) = %v, want nil", i, tc.maxW, name, tc.name, from, to, got)
+					}
+					continue
+				}
+				if l, ok := pathLength(wg, got); gotDist != want || !ok || l != want || got[0] != from || got[len(got)-1] != to {
+					t.Errorf("Graph #%d with weights up to %d, %s: %s(g, %d, %d) = %v, %d, want a path of length %d", i, tc.maxW, name, tc.name, from, to, got, gotDist, want)
+				}
+			}
+		}
+	}
+}
+
+func TestZeroOnePath(t *testing.T) {
+	g := graph.NewWeightedAdjList[float64](4)
+	g.AddEdge(0, 3, 1)
+	g.AddEdge(0, 1, 0)
+	g.AddEdge(1, 2, 0)
+	g.AddEdge(2, 3, 0)
+	if got, dist := ZeroOnePath[float64](g, 0, 3); !reflect.DeepEqual(got, []int{0, 1, 2, 3}) || dist != 0 {
+		t.Errorf("ZeroOnePath(g, 0, 3) = %v, %v, want [0 1 2 3], 0", got, dist)
+	}
+	g.AddEdge(3, 0, 2)
+	defer func() {
+		if recover() == nil {
+			t.Errorf("ZeroOnePath on a graph with an edge of weight 2 did not panic")
+		}
+	}()
+	ZeroOnePath[float64](g, 3, 1)
+}
+
+func TestDialPathNegativeWeight(t *testing.T) {
+	g := graph.NewWeightedAdjList[int8](2)
+	g.AddEdge(0, 1, -1)
+	defer func() {
+		if recover() == nil {
+			t.Errorf("DialPath on a graph with a negative edge did not panic")
+		}
+	}()
+	DialPath[int8](g, 0, 1)
+}
+
+func TestDialPathHugeWeight(t *testing.T) {
+	g := graph.NewWeightedAdjList[uint64](3)
+	g.AddEdge(0, 1, 1)
+	g.AddEdge(1, 2, math.MaxUint64)
+	defer func() {
+		if recover() == nil {
+			t.Errorf("DialPath on a graph with a weight above math.MaxInt did not panic")
+		}
+	}()
+	DialPath[uint64](g, 0, 2)
+}
+
+func TestDeque(t *testing.T) {
+	var d deque[int]
+	var want []int
+	r := rand.New(rand.NewPCG(3, 4))
+	for i := range 1000 {
+		switch r.IntN(3) {
+		case 0:
+			d.pushFront(i)
+			want = append([]int{i}, want...)
+		case 1:
+			d.pushBack(i)
+			want = append(want, i)
+		default:
+			if len(want) == 0 {
+				continue
+			}
+			if got := d.popFront(); got != want[0] {
+				t.Fatalf("Operation #%d: popFront() = %d, want %d", i, got, want[0])
+			}
+			want = want[1:]
+		}
+		if got := d.len(); got != len(want) {
+			t.Fatalf("Operation #%d: len() = %d, want %d", i, got, len(want))
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Package dfs implements depth-first search algorithm.
+package dfs
+
+import (
+	"iter"
+	"strconv"
+
+	"github.com/mabu/algo/graph"
+)
+
+// Event tells at which point of a depth-first search a node is visited.
+type Event int
+
+const (
+	// Pre is when the node is entered, before its descendants.
+	Pre Event = iota
+	// Post is when the node is left, after all its descendants.
+	Post
+)
+
+func (e Event) String() string {
+	switch e {
+	case Pre:
+		return "Pre"
+	case Post:
+		return "Post"
+	}
+	return "Event(" + strconv.Itoa(int(e)) + ")"
+}
+
+// Walk returns an iterator over the nodes reachable from start in depth-first order.
+// Every node is yielded twice: with Pre when it is entered, and with Post when it is left.
+// Adjacent nodes are entered in the order returned by g.Adjacent.
+// Does not use recursion, so deep graphs do not grow the call stack.
+// May be more efficient if g implements graph.Sized.
+func Walk(g graph.Graph, start int) iter.Seq2[int, Event] {
+	return func(yield func(int, Event) bool) {
+		visited := newVisited(g)
+		visited.add(start)
+		if !yield(start, Pre) {
+			return
+		}
+		type frame struct {
+			v   int
+			adj []int // The adjacent nodes that were not tried yet.
+		}
+		stack := []frame{{start, g.Adjacent(start)}}
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			if len(top.adj) == 0 {
+				v := top.v
+				stack = stack[:len(stack)-1]
+				if !yield(v, Post) {
+					return
+				}
+				continue
+			}
+			u := top.adj[0]
+			top.adj = top.adj[1:]
+			if visited.has(u) {
+				continue
+			}
+			visited.add(u)
+			if !yield(u, Pre) {
+				return
+			}
+			stack = append(stack, frame{u, g.Adjacent(u)})
+		}
+	}
+}
+
+type visited interface {
+	has(int) bool
+	add(int)
+}
+
+// newVisited returns a visitedSlice if g implements graph.Sized, otherwise a visitedMap.
+func newVisited(g graph.Graph) visited {
+	if s, ok := g.(graph.Sized); ok {
+		return make(visitedSlice, s.Size())
+	}
+	return make(visitedMap)
+}
+
+type visitedMap map[int]bool
+
+func (m visitedMap) has(v int) bool { return m[v] }
+func (m visitedMap) add(v int)      { m[v] = true }
+
+type visitedSlice []bool
+
+func (s visitedSlice) has(v int) bool { return s[v] }
+func (s visitedSlice) add(v int)      { s[v] = true }
@@ -0,0 +1,80 @@
+package dfs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mabu/algo/graph"
+)
+
+type unsized [][]int
+
+func (g unsized) Adjacent(v int) []int {
+	return g[v]
+}
+
+func TestWalk(t *testing.T) {
+	g := [][]int{
+		{1, 2},
+		{3, 0},
+		{3},
+		{4, 1},
+		{},
+		{0},
+	}
+	type step struct {
+		v int
+		e Event
+	}
+	for _, tc := range []struct {
+		start int
+		limit int // Number of steps before breaking, or 0 to not break.
+		want  []step
+	}{
+		{
+			start: 0,
+			want: []step{
+				{0, Pre}, {1, Pre}, {3, Pre}, {4, Pre}, {4, Post}, {3, Post}, {1, Post},
+				{2, Pre}, {2, Post}, {0, Post},
+			},
+		},
+		{start: 0, limit: 4, want: []step{{0, Pre}, {1, Pre}, {3, Pre}, {4, Pre}}},
+		{start: 0, limit: 6, want: []step{{0, Pre}, {1, Pre}, {3, Pre}, {4, Pre}, {4, Post}, {3, Post}}},
+		{start: 4, want: []step{{4, Pre}, {4, Post}}},
+		{start: 4, limit: 1, want: []step{{4, Pre}}},
+	} {
+		for name, g := range map[string]graph.Graph{
+			"Unsized": unsized(g),
+			"Sized":   graph.AdjList(g),
+		} {
+			var got []step
+			for v, e := range Walk(g, tc.start) {
+				got = append(got, step{v, e})
+				if len(got) == tc.limit {
+					break
+				}
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("%s Walk(g, %d) with limit %d = %v, want %v", name, tc.start, tc.limit, got, tc.want)
+			}
+		}
+	}
+}
+
+func TestWalkDeep(t *testing.T) {
+	const n = 1000000
+	g := make(graph.AdjList, n)
+	for v := range n - 1 {
+		g[v] = []int{v + 1}
+	}
+	steps := 0
+	for v, e := range Walk(g, 0) {
+		if want := steps; e == Pre && v != want || e == Post && v != 2*n-1-steps {
+			t.Fatalf("Step #%d of Walk on a path = %d, %v", steps, v, e)
+		}
+		steps++
+	}
+	if steps != 2*n {
+		t.Errorf("Walk on a path of %d nodes took %d steps, want %d", n, steps, 2*n)
+	}
+}
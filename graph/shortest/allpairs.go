@@ -0,0 +1,188 @@
+package shortest
+
+import (
+	"slices"
+
+	"github.com/mabu/algo/graph"
+)
+
+// Sized is a weighted graph.Sized.
+type Sized[W graph.Number] interface {
+	graph.Weighted[W]
+	Size() int
+}
+
+// Matrix holds the shortest paths between all pairs of nodes of a graph.
+type Matrix[W graph.Number] struct {
+	n    int
+	dist []W
+	// parent[from*n+to] is the node before to on the shortest path from from,
+	// or -1 if to is not reachable.
+	parent []int
+}
+
+func newMatrix[W graph.Number](n int) *Matrix[W] {
+	m := &Matrix[W]{
+		n:      n,
+		dist:   make([]W, n*n),
+		parent: make([]int, n*n),
+	}
+	for i := range m.parent {
+		m.parent[i] = -1
+	}
+	for v := range n {
+		m.parent[v*n+v] = v
+	}
+	return m
+}
+
+// Size returns the number of nodes in the graph.
+func (m *Matrix[W]) Size() int {
+	return m.n
+}
+
+// Dist returns the length of the shortest path from from to to and true.
+// If to is not reachable from from, returns false.
+func (m *Matrix[W]) Dist(from, to int) (W, bool) {
+	i := from*m.n + to
+	return m.dist[i], m.parent[i] != -1
+}
+
+// Path returns the shortest path from from to to, including both endpoints.
+// If to is not reachable from from, returns nil.
+func (m *Matrix[W]) Path(from, to int) []int {
+	if m.parent[from*m.n+to] == -1 {
+		return nil
+	}
+	res := []int{to}
+	for v := to; v != from; {
+		v = m.parent[from*m.n+v]
+		res = append(res, v)
+	}
+	slices.Reverse(res)
+	return res
+}
+
+// FloydWarshall returns the shortest paths between all pairs of nodes, and nil.
+// If g has a cycle with a negative total weight,
+// returns nil and the nodes of such a cycle, in the same format as BellmanFord.
+// Takes O(n³) time and O(n²) memory, so it is best suited for dense graphs.
+func FloydWarshall[W graph.Number](g Sized[W]) (*Matrix[W], []int) {
+	n := g.Size()
+	m := newMatrix[W](n)
+	for v := range n {
+		weights := g.Weights(v)
+		for i, u := range g.Adjacent(v) {
+			if j := v*n + u; m.parent[j] == -1 || weights[i] < m.dist[j] {
+				m.dist[j], m.parent[j] = weights[i], v
+			}
+		}
+	}
+	for k := range n {
+		for i := range n {
+			ik := i*n + k
+			if m.parent[ik] == -1 {
+				continue
+			}
+			for j := range n {
+				kj, ij := k*n+j, i*n+j
+				if m.parent[kj] == -1 {
+					continue
+				}
+				if d := m.dist[ik] + m.dist[kj]; m.parent[ij] == -1 || d < m.dist[ij] {
+					m.dist[ij], m.parent[ij] = d, m.parent[kj]
+				}
+			}
+		}
+		// Stop before the distances around a negative cycle get out of hand.
+		for v := range n {
+			if m.dist[v*n+v] < 0 {
+				_, cycle := BellmanFord[W](g, v)
+				return nil, cycle
+			}
+		}
+	}
+	return m, nil
+}
+
+// Johnson returns the shortest paths between all pairs of nodes, and nil.
+// If g has a cycle with a negative total weight,
+// returns nil and the nodes of such a cycle, in the same format as BellmanFord.
+//
+// It runs BellmanFord once to make all weights non-negative,
+// and then Dijkstra from every node, which is faster than FloydWarshall on sparse graphs.
+func Johnson[W graph.Number](g Sized[W]) (*Matrix[W], []int) {
+	n := g.Size()
+	// The potential of every node is its distance from a new node
+	// that has edges with weight 0 to all nodes.
+	tree, cycle := BellmanFord[W](withSource[W]{g}, n)
+	if cycle != nil {
+		return nil, cycle
+	}
+	potential := make([]W, n)
+	for v := range potential {
+		potential[v], _ = tree.Dist(v)
+	}
+	rg := reweighted[W]{g, potential}
+	m := newMatrix[W](n)
+	for s := range n {
+		tree := Dijkstra[W](rg, s)
+		for v := range n {
+			d, ok := tree.Dist(v)
+			if !ok {
+				continue
+			}
+			sv := s*n + v
+			m.dist[sv] = d - potential[s] + potential[v]
+			if v != s {
+				m.parent[sv], _ = tree.Parent(v)
+			}
+		}
+	}
+	return m, nil
+}
+
+// withSource is g with an extra node Size() that has edges with weight 0 to all other nodes.
+type withSource[W graph.Number] struct {
+	g Sized[W]
+}
+
+func (g withSource[W]) Size() int { return g.g.Size() + 1 }
+
+func (g withSource[W]) Adjacent(v int) []int {
+	if v < g.g.Size() {
+		return g.g.Adjacent(v)
+	}
+	res := make([]int, v)
+	for i := range res {
+		res[i] = i
+	}
+	return res
+}
+
+func (g withSource[W]) Weights(v int) []W {
+	if v < g.g.Size() {
+		return g.g.Weights(v)
+	}
+	return make([]W, v)
+}
+
+// reweighted is g with the weight of every edge from v to u increased by potential[v] - potential[u],
+// which does not change the shortest paths.
+type reweighted[W graph.Number] struct {
+	g         Sized[W]
+	potential []W
+}
+
+func (g reweighted[W]) Size() int            { return g.g.Size() }
+func (g reweighted[W]) Adjacent(v int) []int { return g.g.Adjacent(v) }
+
+func (g reweighted[W]) Weights(v int) []W {
+	weights := g.g.Weights(v)
+	res := make([]W, len(weights))
+	for i, u := range g.g.Adjacent(v) {
+		// The new weights are non-negative, except for rounding errors of floating-point numbers.
+		res[i] = max(0, weights[i]+g.potential[v]-g.potential[u])
+	}
+	return res
+}
@@ -0,0 +1,79 @@
+package shortest
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	gcmp "github.com/google/go-cmp/cmp"
+)
+
+func TestAllPairs(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	cycles := 0
+	for i := range 200 {
+		g := randomGraph(r, 1+r.IntN(15), r.IntN(40), -2, 10)
+		wantCycle := false
+		for s := range g.Size() {
+			wantCycle = wantCycle || hasNegativeCycle(g, s)
+		}
+		var want []map[int]int
+		if wantCycle {
+			cycles++
+		} else {
+			want = allPairs(g)
+		}
+		for name, allPairs := range map[string]func(Sized[int]) (*Matrix[int], []int){
+			"FloydWarshall": FloydWarshall[int],
+			"Johnson":       Johnson[int],
+		} {
+			desc := fmt.Sprintf("Graph #%d: %s(g)", i, name)
+			m, cycle := allPairs(g)
+			if wantCycle {
+				if m != nil {
+					t.Errorf("%s returned a matrix, want a negative cycle", desc)
+				}
+				checkCycle(t, g, cycle, desc)
+				continue
+			}
+			if m == nil || cycle != nil {
+				t.Errorf("%s = %v, %v, want a matrix and no cycle", desc, m, cycle)
+				continue
+			}
+			if got := m.Size(); got != g.Size() {
+				t.Errorf("%s.Size() = %d, want %d", desc, got, g.Size())
+			}
+			for from := range g.Size() {
+				for to := range g.Size() {
+					wantDist, wantOK := want[from][to]
+					if got, ok := m.Dist(from, to); got != wantDist || ok != wantOK {
+						t.Errorf("%s.Dist(%d, %d) = %d, %t, want %d, %t", desc, from, to, got, ok, wantDist, wantOK)
+					}
+					if wantOK {
+						checkPath(t, g, m.Path(from, to), from, to, wantDist, fmt.Sprintf("%s.Path(%d, %d)", desc, from, to))
+					} else if got := m.Path(from, to); got != nil {
+						t.Errorf("%s.Path(%d, %d) = %v, want nil", desc, from, to, got)
+					}
+				}
+			}
+		}
+	}
+	if cycles == 0 || cycles == 200 {
+		t.Errorf("%d of the random graphs have negative cycles, want some but not all", cycles)
+	}
+}
+
+func TestJohnsonFloat(t *testing.T) {
+	g := newGraph(3, edge[float64]{0, 1, 0.1}, edge[float64]{1, 2, -0.4}, edge[float64]{0, 2, -0.2})
+	m, cycle := Johnson(g)
+	if cycle != nil {
+		t.Fatalf("Johnson(g) returned cycle %v", cycle)
+	}
+	if got, ok := m.Dist(0, 2); !ok || math.Abs(got+0.3) > 1e-9 {
+		t.Errorf("Dist(0, 2) = %v, %t, want about -0.3, true", got, ok)
+	}
+	if diff := gcmp.Diff([]int{0, 1, 2}, m.Path(0, 2)); diff != "" {
+		t.Errorf("Path(0, 2) diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,57 @@
+package shortest
+
+import "github.com/mabu/algo/graph"
+
+// AStar returns one of the shortest paths from from to to, including both endpoints, and its length.
+// If the path does not exist, returns nil.
+//
+// h estimates the length of the shortest path from v to to,
+// and nodes with smaller dist(from, v) + h(v) are explored first.
+// The result is the shortest path if h is admissible, i.e. it never overestimates.
+// If h is also consistent, i.e. h(v) <= w(v, u) + h(u) for every edge,
+// every node is explored at most once, like in Dijkstra.
+// Otherwise nodes are explored again when shorter paths to them are found.
+// h must return the same value every time it is called with the same node.
+//
+// Panics if it finds an edge with a negative weight.
+// May be more efficient if g implements graph.Sized.
+func AStar[W graph.Number](g graph.Weighted[W], from, to int, h func(v int) W) ([]int, W) {
+	ls := newLabels[W](g)
+	ls.set(from, label[W]{parent: from, reached: true})
+	frontier := newFrontier[W]()
+	frontier.Insert(entry[W]{h(from), from})
+	for {
+		e, ok := frontier.Min()
+		if !ok {
+			return nil, 0
+		}
+		frontier.Delete(e)
+		v := e.v
+		lv := ls.get(v)
+		if v == to {
+			return path(ls, from, to), lv.dist
+		}
+		lv.done = true
+		ls.set(v, lv)
+		weights := g.Weights(v)
+		for i, u := range g.Adjacent(v) {
+			w := weights[i]
+			if w < 0 {
+				panic("shortest: negative edge weight")
+			}
+			d := lv.dist + w
+			lu := ls.get(u)
+			if lu.reached {
+				if lu.dist <= d {
+					continue
+				}
+				if !lu.done {
+					frontier.Delete(entry[W]{lu.dist + h(u), u})
+				}
+			}
+			// If u was done, it is reopened.
+			ls.set(u, label[W]{dist: d, parent: v, reached: true})
+			frontier.Insert(entry[W]{d + h(u), u})
+		}
+	}
+}
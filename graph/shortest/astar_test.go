@@ -0,0 +1,101 @@
+package shortest
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+
+	"github.com/mabu/algo/graph"
+)
+
+func TestAStarGrid(t *testing.T) {
+	const w, h = 20, 15
+	g := graph.NewWeightedAdjList[int](w * h)
+	r := rand.New(rand.NewPCG(1, 2))
+	for y := range h {
+		for x := range w {
+			v := y*w + x
+			if r.IntN(4) == 0 {
+				continue // A wall.
+			}
+			if x+1 < w {
+				g.AddEdge(v, v+1, 1)
+				g.AddEdge(v+1, v, 1)
+			}
+			if y+1 < h {
+				g.AddEdge(v, v+w, 1)
+				g.AddEdge(v+w, v, 1)
+			}
+		}
+	}
+	abs := func(x int) int { return max(x, -x) }
+	for range 100 {
+		from, to := r.IntN(w*h), r.IntN(w*h)
+		manhattan := func(v int) int { return abs(v%w-to%w) + abs(v/w-to/w) }
+		wantPath, want := Path[int](g, from, to)
+		for name, wg := range graphs(g) {
+			got, gotDist := AStar(wg, from, to, manhattan)
+			desc := fmt.Sprintf("%s: AStar(g, %d, %d, manhattan)", name, from, to)
+			if wantPath == nil {
+				if got != nil {
+					t.Errorf("%s = %v, want nil", desc, got)
+				}
+				continue
+			}
+			if gotDist != want {
+				t.Errorf("%s returned length %d, want %d", desc, gotDist, want)
+			}
+			checkPath(t, wg, got, from, to, want, desc)
+		}
+	}
+}
+
+func TestAStarInconsistent(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	for i := range 200 {
+		g := randomGraph(r, 1+r.IntN(30), r.IntN(100), 0, 10)
+		from, to := r.IntN(g.Size()), r.IntN(g.Size())
+		// The heuristic is a random fraction of the real distance to to, which is admissible but not consistent.
+		rev := graph.NewWeightedAdjList[int](g.Size())
+		for v := range g.Size() {
+			for j, u := range g.Adjacent(v) {
+				rev.AddEdge(u, v, g.Weights(v)[j])
+			}
+		}
+		toDist := distances(rev, to)
+		fraction := make([]float64, g.Size())
+		for v := range fraction {
+			fraction[v] = r.Float64()
+		}
+		h := func(v int) int { return int(float64(toDist[v]) * fraction[v]) }
+		wantDist, wantOK := distances(g, from)[to]
+		for name, wg := range graphs(g) {
+			got, gotDist := AStar(wg, from, to, h)
+			desc := fmt.Sprintf("Graph #%d, %s: AStar(g, %d, %d, h)", i, name, from, to)
+			if !wantOK {
+				if got != nil {
+					t.Errorf("%s = %v, want nil", desc, got)
+				}
+				continue
+			}
+			if gotDist != wantDist {
+				t.Errorf("%s returned length %d, want %d", desc, gotDist, wantDist)
+			}
+			checkPath(t, wg, got, from, to, wantDist, desc)
+		}
+	}
+}
+
+func TestAStarReopen(t *testing.T) {
+	// The heuristic makes 2 look better than 1, so 3 is first reached through 2,
+	// and has to be explored again when the shorter path through 1 is found.
+	g := newGraph(5, edge[int]{0, 1, 1}, edge[int]{0, 2, 1}, edge[int]{1, 3, 1}, edge[int]{2, 3, 3}, edge[int]{3, 4, 5})
+	h := func(v int) int { return []int{0, 6, 0, 0, 0}[v] }
+	for name, wg := range graphs(g) {
+		got, gotDist := AStar(wg, 0, 4, h)
+		if want := []int{0, 1, 3, 4}; !reflect.DeepEqual(got, want) || gotDist != 7 {
+			t.Errorf("%s: AStar(g, 0, 4, h) = %v, %d, want %v, 7", name, got, gotDist, want)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package shortest
+
+import (
+	"slices"
+
+	"github.com/mabu/algo/graph"
+)
+
+// BellmanFord returns the shortest paths from source to all nodes reachable from it, and nil.
+// Unlike Dijkstra, it allows edges with negative weights.
+//
+// If a cycle with a negative total weight is reachable from source,
+// the shortest paths are not defined, so it returns nil and the nodes of such a cycle:
+// there is an edge from every node to the next one, and from the last node to the first one.
+//
+// Only the nodes whose distances change are revisited (the optimization known as SPFA),
+// so it is often much faster than the O(nm) worst case.
+// May be more efficient if g implements graph.Sized.
+func BellmanFord[W graph.Number](g graph.Weighted[W], source int) (*Tree[W], []int) {
+	ls := newLabels[W](g)
+	ls.set(source, label[W]{parent: source, reached: true})
+	states := newNodes[state](g)
+	states.set(source, state{queued: true})
+	reached := []int{source}
+	queue := []int{source}
+	// Cycles in the parent pointers have negative weights.
+	// Looking for them after every len(reached) relaxations
+	// finds a negative cycle soon after the relaxations start going around it,
+	// and takes O(1) amortized time per relaxation.
+	relaxed, walk := 0, 0
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		lv := ls.get(v)
+		sv := states.get(v)
+		sv.queued = false
+		states.set(v, sv)
+		weights := g.Weights(v)
+		for i, u := range g.Adjacent(v) {
+			d := lv.dist + weights[i]
+			lu := ls.get(u)
+			if lu.reached && lu.dist <= d {
+				continue
+			}
+			if !lu.reached {
+				reached = append(reached, u)
+			}
+			lu.dist, lu.parent, lu.reached = d, v, true
+			ls.set(u, lu)
+			if su := states.get(u); !su.queued {
+				su.queued = true
+				states.set(u, su)
+				queue = append(queue, u)
+			}
+			if relaxed++; u == source || relaxed >= len(reached) {
+				relaxed = 0
+				// Once the distance to source decreases, source has a parent, too.
+				if cycle := findCycle(ls, states, reached, source, u != source, &walk); cycle != nil {
+					return nil, cycle
+				}
+			}
+		}
+	}
+	return &Tree[W]{source: source, labels: ls}, nil
+}
+
+// state is what BellmanFord knows about a node in addition to its label.
+type state struct {
+	queued bool // Whether the node is in the queue.
+	walk   int  // The last walk of findCycle that visited the node.
+}
+
+// findCycle returns a cycle in the parent pointers of the reached nodes, or nil if there is none.
+// If hasRoot is true, source has no parent.
+// walk is the last number that was used to mark the visited nodes.
+func findCycle[W graph.Number](ls labels[W], states nodes[state], reached []int, source int, hasRoot bool, walk *int) []int {
+	first := *walk + 1
+	for _, v := range reached {
+		*walk++
+		for !hasRoot || v != source {
+			s := states.get(v)
+			if s.walk == *walk {
+				return parentCycle(ls, v)
+			}
+			if s.walk >= first {
+				break // The rest of the path was visited by an earlier walk.
+			}
+			s.walk = *walk
+			states.set(v, s)
+			v = ls.get(v).parent
+		}
+	}
+	return nil
+}
+
+// parentCycle returns the cycle formed by the parent pointers that starts at v.
+func parentCycle[W graph.Number](ls labels[W], v int) []int {
+	cycle := []int{v}
+	for u := ls.get(v).parent; u != v; u = ls.get(u).parent {
+		cycle = append(cycle, u)
+	}
+	slices.Reverse(cycle)
+	return cycle
+}
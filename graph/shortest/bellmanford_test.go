@@ -0,0 +1,129 @@
+package shortest
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/mabu/algo/graph"
+)
+
+// hasNegativeCycle reports whether a cycle with negative weight is reachable from s,
+// by checking whether the distances still change after Size() rounds of relaxing all edges.
+func hasNegativeCycle(g *graph.WeightedAdjList[int], s int) bool {
+	dist := map[int]int{s: 0}
+	changed := true
+	for range g.Size() + 1 {
+		changed = false
+		for v := range g.Size() {
+			dv, ok := dist[v]
+			if !ok {
+				continue
+			}
+			for i, u := range g.Adjacent(v) {
+				if du, ok := dist[u]; !ok || dv+g.Weights(v)[i] < du {
+					dist[u] = dv + g.Weights(v)[i]
+					changed = true
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// checkCycle verifies that cycle is a cycle in g with a negative weight.
+func checkCycle(t *testing.T, g graph.Weighted[int], cycle []int, desc string) {
+	t.Helper()
+	if len(cycle) == 0 {
+		t.Errorf("%s returned an empty cycle", desc)
+		return
+	}
+	if got, ok := pathLength(g, append(cycle, cycle[0])); !ok || got >= 0 {
+		t.Errorf("%s returned cycle %v, which has length %d (exists: %t), want a negative length", desc, cycle, got, ok)
+	}
+}
+
+func TestBellmanFord(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	cycles := 0
+	for i := range 300 {
+		g := randomGraph(r, 1+r.IntN(20), r.IntN(50), -3, 10)
+		s := r.IntN(g.Size())
+		wantCycle := hasNegativeCycle(g, s)
+		var want map[int]int
+		if wantCycle {
+			cycles++
+		} else {
+			want = distances(g, s)
+		}
+		for name, wg := range graphs(g) {
+			desc := fmt.Sprintf("Graph #%d, %s: BellmanFord(g, %d)", i, name, s)
+			tree, cycle := BellmanFord(wg, s)
+			if wantCycle {
+				if tree != nil {
+					t.Errorf("%s returned a tree, want a negative cycle", desc)
+				}
+				checkCycle(t, wg, cycle, desc)
+				continue
+			}
+			if tree == nil || cycle != nil {
+				t.Errorf("%s = %v, %v, want a tree and no cycle", desc, tree, cycle)
+				continue
+			}
+			for v := range g.Size() {
+				wantDist, wantOK := want[v]
+				if got, ok := tree.Dist(v); got != wantDist || ok != wantOK {
+					t.Errorf("%s.Dist(%d) = %d, %t, want %d, %t", desc, v, got, ok, wantDist, wantOK)
+				}
+				if wantOK {
+					checkPath(t, wg, tree.PathTo(v), s, v, wantDist, fmt.Sprintf("%s.PathTo(%d)", desc, v))
+				} else if got := tree.PathTo(v); got != nil {
+					t.Errorf("%s.PathTo(%d) = %v, want nil", desc, v, got)
+				}
+			}
+		}
+	}
+	if cycles == 0 || cycles == 300 {
+		t.Errorf("%d of the random graphs have negative cycles, want some but not all", cycles)
+	}
+}
+
+func TestBellmanFordCycles(t *testing.T) {
+	for _, tc := range []struct {
+		desc   string
+		size   int
+		edges  []edge[int]
+		source int
+	}{
+		{
+			desc:  "self-loop at the source",
+			size:  1,
+			edges: []edge[int]{{0, 0, -1}},
+		},
+		{
+			desc:  "self-loop",
+			size:  2,
+			edges: []edge[int]{{0, 1, 5}, {1, 1, -1}},
+		},
+		{
+			desc:  "through the source",
+			size:  3,
+			edges: []edge[int]{{0, 1, 1}, {1, 2, 1}, {2, 0, -3}},
+		},
+		{
+			desc:   "behind a positive cycle",
+			size:   5,
+			edges:  []edge[int]{{0, 1, 1}, {1, 0, 1}, {1, 2, 1}, {2, 3, -2}, {3, 4, 0}, {4, 2, 1}},
+			source: 1,
+		},
+	} {
+		for name, g := range graphs(newGraph(tc.size, tc.edges...)) {
+			tree, cycle := BellmanFord(g, tc.source)
+			desc := fmt.Sprintf("%s, %s: BellmanFord(g, %d)", tc.desc, name, tc.source)
+			if tree != nil {
+				t.Errorf("%s returned a tree, want a negative cycle", desc)
+			}
+			checkCycle(t, g, cycle, desc)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package shortest
+
+import (
+	"cmp"
+
+	"github.com/mabu/algo/graph"
+	"github.com/mabu/algo/sorted"
+)
+
+// Dijkstra returns the shortest paths from source to all nodes reachable from it.
+// Panics if it finds an edge with a negative weight.
+// May be more efficient if g implements graph.Sized.
+func Dijkstra[W graph.Number](g graph.Weighted[W], source int) *Tree[W] {
+	return &Tree[W]{
+		source: source,
+		labels: dijkstra(g, source, source, false),
+	}
+}
+
+// Path returns one of the shortest paths from from to to, including both endpoints, and its length.
+// If the path does not exist, returns nil.
+// The search stops as soon as the distance to to is known.
+// Panics if it finds an edge with a negative weight.
+// May be more efficient if g implements graph.Sized.
+func Path[W graph.Number](g graph.Weighted[W], from, to int) ([]int, W) {
+	ls := dijkstra(g, from, to, true)
+	l := ls.get(to)
+	if !l.done {
+		return nil, 0
+	}
+	return path(ls, from, to), l.dist
+}
+
+// dijkstra labels the nodes reachable from source.
+// If stop is true, returns as soon as target is done.
+func dijkstra[W graph.Number](g graph.Weighted[W], source, target int, stop bool) labels[W] {
+	ls := newLabels[W](g)
+	ls.set(source, label[W]{parent: source, reached: true})
+	frontier := newFrontier[W]()
+	frontier.Insert(entry[W]{0, source})
+	for {
+		e, ok := frontier.Min()
+		if !ok {
+			return ls
+		}
+		frontier.Delete(e)
+		v := e.v
+		lv := ls.get(v)
+		lv.done = true
+		ls.set(v, lv)
+		if stop && v == target {
+			return ls
+		}
+		weights := g.Weights(v)
+		for i, u := range g.Adjacent(v) {
+			w := weights[i]
+			if w < 0 {
+				panic("shortest: negative edge weight")
+			}
+			d := lv.dist + w
+			lu := ls.get(u)
+			if lu.reached {
+				if lu.done || lu.dist <= d {
+					continue
+				}
+				frontier.Delete(entry[W]{lu.dist, u})
+			}
+			ls.set(u, label[W]{dist: d, parent: v, reached: true})
+			frontier.Insert(entry[W]{d, u})
+		}
+	}
+}
+
+// entry is a node in the frontier of a search, ordered by priority.
+type entry[W graph.Number] struct {
+	priority W
+	v        int
+}
+
+func newFrontier[W graph.Number]() *sorted.Set[entry[W]] {
+	return sorted.NewSetFunc(func(a, b entry[W]) int {
+		if c := cmp.Compare(a.priority, b.priority); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.v, b.v)
+	})
+}
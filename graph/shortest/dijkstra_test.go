@@ -0,0 +1,128 @@
+package shortest
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+)
+
+func TestDijkstra(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for i := range 50 {
+		g := randomGraph(r, 1+r.IntN(30), r.IntN(100), 0, 10)
+		want := allPairs(g)
+		for name, wg := range graphs(g) {
+			for s := range g.Size() {
+				tree := Dijkstra(wg, s)
+				if got := tree.Source(); got != s {
+					t.Errorf("Graph #%d, %s: Dijkstra(g, %d).Source() = %d", i, name, s, got)
+				}
+				for v := range g.Size() {
+					wantDist, wantOK := want[s][v]
+					if got, ok := tree.Dist(v); got != wantDist || ok != wantOK {
+						t.Errorf("Graph #%d, %s: Dijkstra(g, %d).Dist(%d) = %d, %t, want %d, %t", i, name, s, v, got, ok, wantDist, wantOK)
+					}
+					path := tree.PathTo(v)
+					if !wantOK {
+						if path != nil {
+							t.Errorf("Graph #%d, %s: Dijkstra(g, %d).PathTo(%d) = %v, want nil", i, name, s, v, path)
+						}
+						continue
+					}
+					checkPath(t, wg, path, s, v, wantDist, fmt.Sprintf("Graph #%d, %s: Dijkstra(g, %d).PathTo(%d)", i, name, s, v))
+					p, ok := tree.Parent(v)
+					if wantOK := v != s; ok != wantOK || ok && p != path[len(path)-2] {
+						t.Errorf("Graph #%d, %s: Dijkstra(g, %d).Parent(%d) = %d, %t, want the node before the last one in %v", i, name, s, v, p, ok, path)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestPath(t *testing.T) {
+	for _, tc := range []struct {
+		desc     string
+		size     int
+		edges    []edge[float64]
+		from, to int
+		want     []int
+		wantDist float64
+	}{
+		{
+			desc: "single node",
+			size: 1,
+			want: []int{0},
+		},
+		{
+			desc:  "unreachable",
+			size:  2,
+			edges: []edge[float64]{{1, 0, 1}},
+			to:    1,
+		},
+		{
+			desc:     "detour",
+			size:     4,
+			edges:    []edge[float64]{{0, 3, 2.5}, {0, 1, 0.5}, {1, 2, 0.5}, {2, 3, 0.5}},
+			to:       3,
+			want:     []int{0, 1, 2, 3},
+			wantDist: 1.5,
+		},
+		{
+			desc:     "zero weights",
+			size:     3,
+			edges:    []edge[float64]{{0, 1, 0}, {1, 0, 0}, {1, 2, 0}},
+			to:       2,
+			want:     []int{0, 1, 2},
+			wantDist: 0,
+		},
+		{
+			desc:     "parallel edges",
+			size:     2,
+			edges:    []edge[float64]{{0, 1, 3}, {0, 1, 1}, {0, 1, 2}},
+			to:       1,
+			want:     []int{0, 1},
+			wantDist: 1,
+		},
+	} {
+		for name, g := range graphs(newGraph(tc.size, tc.edges...)) {
+			got, gotDist := Path(g, tc.from, tc.to)
+			if !reflect.DeepEqual(got, tc.want) || gotDist != tc.wantDist {
+				t.Errorf("%s, %s: Path(g, %d, %d) = %v, %v, want %v, %v", tc.desc, name, tc.from, tc.to, got, gotDist, tc.want, tc.wantDist)
+			}
+		}
+	}
+}
+
+func TestPathRandom(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	for i := range 200 {
+		g := randomGraph(r, 1+r.IntN(30), r.IntN(100), 0, 10)
+		want := allPairs(g)
+		from, to := r.IntN(g.Size()), r.IntN(g.Size())
+		wantDist, wantOK := want[from][to]
+		for name, wg := range graphs(g) {
+			got, gotDist := Path(wg, from, to)
+			if !wantOK {
+				if got != nil {
+					t.Errorf("Graph #%d, %s: Path(g, %d, %d) = %v, want nil", i, name, from, to, got)
+				}
+				continue
+			}
+			if gotDist != wantDist {
+				t.Errorf("Graph #%d, %s: Path(g, %d, %d) returned length %d, want %d", i, name, from, to, gotDist, wantDist)
+			}
+			checkPath(t, wg, got, from, to, wantDist, fmt.Sprintf("Graph #%d, %s: Path(g, %d, %d)", i, name, from, to))
+		}
+	}
+}
+
+func TestDijkstraNegativeWeight(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Dijkstra on a graph with a negative edge did not panic")
+		}
+	}()
+	Dijkstra(newGraph(2, edge[int]{0, 1, -1}), 0)
+}
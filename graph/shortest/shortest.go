@@ -0,0 +1,103 @@
+// Package shortest implements shortest path algorithms for weighted graphs.
+package shortest
+
+import (
+	"slices"
+
+	"github.com/mabu/algo/graph"
+)
+
+// Tree holds the shortest paths from a source node to all nodes reachable from it.
+type Tree[W graph.Number] struct {
+	source int
+	labels labels[W]
+}
+
+// Source returns the node where the paths start.
+func (t *Tree[W]) Source() int {
+	return t.source
+}
+
+// Dist returns the length of the shortest path from the source to v and true.
+// If v is not reachable, returns false.
+func (t *Tree[W]) Dist(v int) (W, bool) {
+	l := t.labels.get(v)
+	return l.dist, l.reached
+}
+
+// Parent returns the node before v on the shortest path from the source to v and true.
+// If v is the source or is not reachable, returns false.
+func (t *Tree[W]) Parent(v int) (int, bool) {
+	l := t.labels.get(v)
+	return l.parent, l.reached && v != t.source
+}
+
+// PathTo returns the shortest path from the source to v, including both endpoints.
+// If v is not reachable, returns nil.
+func (t *Tree[W]) PathTo(v int) []int {
+	if !t.labels.get(v).reached {
+		return nil
+	}
+	return path(t.labels, t.source, v)
+}
+
+// label is what a search knows about a node.
+type label[W graph.Number] struct {
+	dist    W
+	parent  int
+	reached bool // Whether dist and parent are set.
+	done    bool // Whether dist is final.
+}
+
+type labels[W graph.Number] interface {
+	nodes[label[W]]
+}
+
+func newLabels[W graph.Number](g graph.Graph) labels[W] {
+	return newNodes[label[W]](g)
+}
+
+// nodes maps the nodes of a graph to the values of type T, which are zero for the nodes that were not set.
+type nodes[T any] interface {
+	get(v int) T
+	set(v int, x T)
+}
+
+// newNodes returns nodes backed by a slice if g implements graph.Sized, otherwise by a map.
+func newNodes[T any](g graph.Graph) nodes[T] {
+	if s, ok := g.(graph.Sized); ok {
+		return make(nodeSlice[T], s.Size())
+	}
+	return make(nodeMap[T])
+}
+
+type nodeMap[T any] map[int]T
+
+func (m nodeMap[T]) get(v int) T {
+	return m[v]
+}
+
+func (m nodeMap[T]) set(v int, x T) {
+	m[v] = x
+}
+
+type nodeSlice[T any] []T
+
+func (s nodeSlice[T]) get(v int) T {
+	return s[v]
+}
+
+func (s nodeSlice[T]) set(v int, x T) {
+	s[v] = x
+}
+
+// path follows the parents from to back to from, and returns the path from from to to.
+func path[W graph.Number](ls labels[W], from, to int) []int {
+	res := []int{to}
+	for v := to; v != from; {
+		v = ls.get(v).parent
+		res = append(res, v)
+	}
+	slices.Reverse(res)
+	return res
+}
@@ -0,0 +1,110 @@
+package shortest
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/mabu/algo/graph"
+)
+
+// unsized hides the Size method of a graph.
+type unsized[W graph.Number] struct {
+	g *graph.WeightedAdjList[W]
+}
+
+func (g unsized[W]) Adjacent(v int) []int { return g.g.Adjacent(v) }
+func (g unsized[W]) Weights(v int) []W    { return g.g.Weights(v) }
+
+// graphs returns g as graph.Sized and as a general graph.Weighted.
+func graphs[W graph.Number](g *graph.WeightedAdjList[W]) map[string]graph.Weighted[W] {
+	return map[string]graph.Weighted[W]{
+		"Sized":   g,
+		"Unsized": unsized[W]{g},
+	}
+}
+
+func newGraph[W graph.Number](size int, edges ...edge[W]) *graph.WeightedAdjList[W] {
+	g := graph.NewWeightedAdjList[W](size)
+	for _, e := range edges {
+		g.AddEdge(e.from, e.to, e.w)
+	}
+	return g
+}
+
+type edge[W graph.Number] struct {
+	from, to int
+	w        W
+}
+
+// randomGraph returns a graph with n nodes and m edges with weights in [minW, maxW].
+func randomGraph(r *rand.Rand, n, m, minW, maxW int) *graph.WeightedAdjList[int] {
+	g := graph.NewWeightedAdjList[int](n)
+	for range m {
+		g.AddEdge(r.IntN(n), r.IntN(n), minW+r.IntN(maxW-minW+1))
+	}
+	return g
+}
+
+// allPairs returns the distances between all pairs of nodes, computed by distances.
+// The graph must not have negative cycles.
+func allPairs(g *graph.WeightedAdjList[int]) []map[int]int {
+	dist := make([]map[int]int, g.Size())
+	for s := range dist {
+		dist[s] = distances(g, s)
+	}
+	return dist
+}
+
+// distances returns the distances from s, computed by relaxing all edges until nothing changes.
+// Unreachable nodes are not in the map. No negative cycle may be reachable from s.
+func distances(g *graph.WeightedAdjList[int], s int) map[int]int {
+	dist := map[int]int{s: 0}
+	for changed := true; changed; {
+		changed = false
+		for v := range g.Size() {
+			dv, ok := dist[v]
+			if !ok {
+				continue
+			}
+			for i, u := range g.Adjacent(v) {
+				if du, ok := dist[u]; !ok || dv+g.Weights(v)[i] < du {
+					dist[u] = dv + g.Weights(v)[i]
+					changed = true
+				}
+			}
+		}
+	}
+	return dist
+}
+
+// pathLength returns the length of the path using the lightest edges between its consecutive nodes,
+// and false if some of the edges do not exist.
+func pathLength[W graph.Number](g graph.Weighted[W], path []int) (W, bool) {
+	var res W
+	for i := 1; i < len(path); i++ {
+		var best W
+		found := false
+		for j, u := range g.Adjacent(path[i-1]) {
+			if w := g.Weights(path[i-1])[j]; u == path[i] && (!found || w < best) {
+				best, found = w, true
+			}
+		}
+		if !found {
+			return res, false
+		}
+		res += best
+	}
+	return res, true
+}
+
+// checkPath verifies that path is a path from from to to in g with length want.
+func checkPath(t *testing.T, g graph.Weighted[int], path []int, from, to, want int, desc string) {
+	t.Helper()
+	if len(path) == 0 || path[0] != from || path[len(path)-1] != to {
+		t.Errorf("%s = %v, want a path from %d to %d", desc, path, from, to)
+		return
+	}
+	if got, ok := pathLength(g, path); !ok || got != want {
+		t.Errorf("%s = %v, which has length %d (exists: %t), want %d", desc, path, got, ok, want)
+	}
+}
@@ -1,6 +1,8 @@
 // Package graph specifies interfaces for graphs with integer-labeled nodes.
 package graph
 
+import "slices"
+
 // Graph is the most general interface for a graph.
 type Graph interface {
 	// Adjacent reports what nodes are adjacent to the node v.
@@ -21,3 +23,114 @@ type AdjList [][]int
 
 func (l AdjList) Adjacent(v int) []int { return l[v] }
 func (l AdjList) Size() int            { return len(l) }
+
+// Number is a constraint for the types of edge weights.
+type Number interface {
+	Integer | ~float32 | ~float64
+}
+
+// Integer is a constraint for integer edge weights.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Weighted is a graph whose edges have weights of type W.
+type Weighted[W Number] interface {
+	Graph
+	// Weights reports the weights of the edges from the node v
+	// to the nodes returned by Adjacent(v), in the same order.
+	Weights(v int) []W
+}
+
+// WeightedAdjList is a weighted graph represented as an adjacency list.
+// Implements Sized. The zero value is an empty graph.
+type WeightedAdjList[W Number] struct {
+	adj     AdjList
+	weights [][]W
+}
+
+// NewWeightedAdjList creates a graph with size nodes and no edges.
+func NewWeightedAdjList[W Number](size int) *WeightedAdjList[W] {
+	return &WeightedAdjList[W]{
+		adj:     make(AdjList, size),
+		weights: make([][]W, size),
+	}
+}
+
+// AddNode adds a node without edges and returns it.
+func (l *WeightedAdjList[W]) AddNode() int {
+	l.adj = append(l.adj, nil)
+	l.weights = append(l.weights, nil)
+	return len(l.adj) - 1
+}
+
+// AddEdge adds an edge from v to u with weight w. Panics if v is not in the graph.
+func (l *WeightedAdjList[W]) AddEdge(v, u int, w W) {
+	l.adj[v] = append(l.adj[v], u)
+	l.weights[v] = append(l.weights[v], w)
+}
+
+func (l *WeightedAdjList[W]) Adjacent(v int) []int { return l.adj[v] }
+func (l *WeightedAdjList[W]) Weights(v int) []W    { return l.weights[v] }
+func (l *WeightedAdjList[W]) Size() int            { return len(l.adj) }
+
+// Unit returns a view of g where every edge has weight 1.
+// The result implements Sized if g does.
+func Unit[W Number](g Graph) Weighted[W] {
+	if s, ok := g.(Sized); ok {
+		return unitSized[W]{s}
+	}
+	return unit[W]{g}
+}
+
+type unit[W Number] struct {
+	Graph
+}
+
+func (g unit[W]) Weights(v int) []W { return ones[W](len(g.Adjacent(v))) }
+
+type unitSized[W Number] struct {
+	Sized
+}
+
+func (g unitSized[W]) Weights(v int) []W { return ones[W](len(g.Adjacent(v))) }
+
+func ones[W Number](n int) []W {
+	res := make([]W, n)
+	for i := range res {
+		res[i] = 1
+	}
+	return res
+}
+
+// Reversible is a graph that also knows the incoming edges of its nodes.
+// An undirected graph can return Adjacent(v) from Predecessors(v).
+type Reversible interface {
+	Graph
+	// Predecessors reports from what nodes there are edges to the node v.
+	Predecessors(v int) []int
+}
+
+// ReversibleAdjList is a graph represented as adjacency lists of both outgoing and incoming edges.
+// Implements Reversible and Sized.
+type ReversibleAdjList struct {
+	out, in AdjList
+}
+
+// NewReversible returns a copy of g that also knows the incoming edges. Takes O(n + m) time.
+func NewReversible(g Sized) *ReversibleAdjList {
+	n := g.Size()
+	l := &ReversibleAdjList{make(AdjList, n), make(AdjList, n)}
+	for v := range n {
+		l.out[v] = slices.Clone(g.Adjacent(v))
+		for _, u := range l.out[v] {
+			l.in[u] = append(l.in[u], v)
+		}
+	}
+	return l
+}
+
+func (l *ReversibleAdjList) Adjacent(v int) []int     { return l.out[v] }
+func (l *ReversibleAdjList) Predecessors(v int) []int { return l.in[v] }
+func (l *ReversibleAdjList) Size() int                { return len(l.out) }
@@ -0,0 +1,83 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+type unsized [][]int
+
+func (g unsized) Adjacent(v int) []int {
+	return g[v]
+}
+
+func TestWeightedAdjList(t *testing.T) {
+	var g WeightedAdjList[float64]
+	if got := g.Size(); got != 0 {
+		t.Errorf("Size() of the zero value = %d, want 0", got)
+	}
+	for i := range 3 {
+		if got := g.AddNode(); got != i {
+			t.Errorf("AddNode() = %d, want %d", got, i)
+		}
+	}
+	g.AddEdge(0, 1, 0.5)
+	g.AddEdge(0, 2, -1)
+	g.AddEdge(2, 0, 3)
+	g.AddEdge(0, 1, 2)
+	var s Sized = &g
+	if got := s.Size(); got != 3 {
+		t.Errorf("Size() = %d, want 3", got)
+	}
+	for _, tc := range []struct {
+		v           int
+		wantAdj     []int
+		wantWeights []float64
+	}{
+		{0, []int{1, 2, 1}, []float64{0.5, -1, 2}},
+		{1, nil, nil},
+		{2, []int{0}, []float64{3}},
+	} {
+		if got := g.Adjacent(tc.v); !reflect.DeepEqual(got, tc.wantAdj) {
+			t.Errorf("Adjacent(%d) = %v, want %v", tc.v, got, tc.wantAdj)
+		}
+		if got := g.Weights(tc.v); !reflect.DeepEqual(got, tc.wantWeights) {
+			t.Errorf("Weights(%d) = %v, want %v", tc.v, got, tc.wantWeights)
+		}
+	}
+}
+
+func TestUnit(t *testing.T) {
+	adj := [][]int{{1, 2}, {}, {0}}
+	for name, g := range map[string]Graph{
+		"unsized": unsized(adj),
+		"sized":   AdjList(adj),
+	} {
+		w := Unit[int](g)
+		if _, ok := w.(Sized); ok != (name == "sized") {
+			t.Errorf("Unit of an %s graph implements Sized: %t", name, ok)
+		}
+		for v, want := range [][]int{{1, 1}, {}, {1}} {
+			if got := w.Weights(v); !reflect.DeepEqual(got, want) {
+				t.Errorf("Unit of an %s graph: Weights(%d) = %v, want %v", name, v, got, want)
+			}
+		}
+	}
+}
+
+func TestNewReversible(t *testing.T) {
+	adj := AdjList{{1, 2}, {2}, {0, 2}}
+	g := NewReversible(adj)
+	adj[0][0] = 2 // Does not change the copy.
+	for v, want := range [][]int{{2}, {0}, {0, 1, 2}} {
+		if got := g.Predecessors(v); !reflect.DeepEqual(got, want) {
+			t.Errorf("Predecessors(%d) = %v, want %v", v, got, want)
+		}
+	}
+	if got := g.Adjacent(0); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("Adjacent(0) = %v, want [1 2]", got)
+	}
+	if got := g.Size(); got != 3 {
+		t.Errorf("Size() = %d, want 3", got)
+	}
+}
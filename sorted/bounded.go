@@ -0,0 +1,131 @@
+package sorted
+
+import (
+	"cmp"
+	"iter"
+)
+
+// Evict specifies which element leaves a Bounded set when it overflows.
+type Evict int
+
+const (
+	// EvictMax evicts the largest element, so that the set keeps the smallest ones.
+	EvictMax Evict = iota
+	// EvictMin evicts the smallest element, so that the set keeps the largest ones.
+	EvictMin
+)
+
+// Bounded is a sorted set that holds at most a fixed number of elements.
+// Inserting to a full set evicts one of the extreme elements.
+type Bounded[T any] struct {
+	s        *Set[T]
+	capacity int
+	evict    Evict
+}
+
+// NewBounded creates a new Bounded set of T with the given capacity, using < for comparisons.
+// Panics if capacity is negative.
+func NewBounded[T cmp.Ordered](capacity int, evict Evict) *Bounded[T] {
+	return newBounded(NewSet[T](), capacity, evict)
+}
+
+// NewBoundedFunc creates a new Bounded set of T with the given capacity, ordered according to cmp.
+// Panics if capacity is negative.
+func NewBoundedFunc[T any](capacity int, evict Evict, cmp func(T, T) int) *Bounded[T] {
+	return newBounded(NewSetFunc(cmp), capacity, evict)
+}
+
+func newBounded[T any](s *Set[T], capacity int, evict Evict) *Bounded[T] {
+	if capacity < 0 {
+		panic("sorted: negative Bounded capacity")
+	}
+	return &Bounded[T]{
+		s:        s,
+		capacity: capacity,
+		evict:    evict,
+	}
+}
+
+// Cap returns the maximum number of elements in the set.
+func (b *Bounded[T]) Cap() int {
+	return b.capacity
+}
+
+// Insert adds x to the set unless an element that compares as equal to x is already there.
+// If that makes the set exceed its capacity, evicts the largest or the smallest element,
+// which may be x itself, and returns it and true.
+// Otherwise returns false.
+func (b *Bounded[T]) Insert(x T) (evicted T, ok bool) {
+	if !b.s.Insert(x) || b.s.Len() <= b.capacity {
+		return evicted, false
+	}
+	var si *SetItem[T]
+	if b.evict == EvictMin {
+		si, _ = b.s.First()
+	} else {
+		si, _ = b.s.Last()
+	}
+	evicted = si.value
+	b.s.Delete(evicted)
+	return evicted, true
+}
+
+// Delete removes x from the set if it exists.
+// The return value indicates whether the removal happened.
+func (b *Bounded[T]) Delete(x T) bool {
+	return b.s.Delete(x)
+}
+
+// Len returns the number of elements in the set.
+func (b *Bounded[T]) Len() int {
+	return b.s.Len()
+}
+
+// Has reports whether x is in the set.
+func (b *Bounded[T]) Has(x T) bool {
+	return b.s.Has(x)
+}
+
+// First returns the smallest SetItem and true, or nil and false if the set is empty.
+//
+// The returned item must not be used to insert elements, as that bypasses the capacity.
+func (b *Bounded[T]) First() (*SetItem[T], bool) {
+	return b.s.First()
+}
+
+// Last returns the largest SetItem and true, or nil and false if the set is empty.
+//
+// The returned item must not be used to insert elements, as that bypasses the capacity.
+func (b *Bounded[T]) Last() (*SetItem[T], bool) {
+	return b.s.Last()
+}
+
+// Min returns the smallest value in the set and true.
+// If the set is empty returns false.
+func (b *Bounded[T]) Min() (T, bool) {
+	return b.s.Min()
+}
+
+// Max returns the largest value in the set and true.
+// If the set is empty returns false.
+func (b *Bounded[T]) Max() (T, bool) {
+	return b.s.Max()
+}
+
+// All returns an iterator over all elements in the set in sorted order.
+func (b *Bounded[T]) All() iter.Seq[T] {
+	return b.s.All()
+}
+
+// Backward returns an iterator over all elements in the set in reverse order.
+func (b *Bounded[T]) Backward() iter.Seq[T] {
+	return b.s.Backward()
+}
+
+// FindGreaterThanOrEqual returns the first SetItem that is greater than or equal to x, and true.
+// If there is no such element, returns false.
+//
+// The returned item must not be used to insert elements, as that bypasses the capacity.
+func (b *Bounded[T]) FindGreaterThanOrEqual(x T) (*SetItem[T], bool) {
+	return b.s.FindGreaterThanOrEqual(x)
+}
@@ -0,0 +1,100 @@
+package sorted
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+
+	gcmp "github.com/google/go-cmp/cmp"
+)
+
+func TestBounded(t *testing.T) {
+	type op struct {
+		x           int
+		wantEvicted int
+		wantOK      bool
+	}
+	for _, tc := range []struct {
+		name    string
+		b       *Bounded[int]
+		ops     []op
+		wantAll []int
+	}{
+		{
+			name: "EvictMax",
+			b:    NewBounded[int](3, EvictMax),
+			ops: []op{
+				{x: 5},
+				{x: 3},
+				{x: 7},
+				{x: 7},
+				{x: 1, wantEvicted: 7, wantOK: true},
+				{x: 9, wantEvicted: 9, wantOK: true},
+				{x: 4, wantEvicted: 5, wantOK: true},
+			},
+			wantAll: []int{1, 3, 4},
+		},
+		{
+			name: "EvictMin",
+			b:    NewBoundedFunc(3, EvictMin, cmp.Compare[int]),
+			ops: []op{
+				{x: 5},
+				{x: 3},
+				{x: 7},
+				{x: 1, wantEvicted: 1, wantOK: true},
+				{x: 9, wantEvicted: 3, wantOK: true},
+				{x: 9},
+				{x: 6, wantEvicted: 5, wantOK: true},
+			},
+			wantAll: []int{6, 7, 9},
+		},
+		{
+			name: "ZeroCapacity",
+			b:    NewBounded[int](0, EvictMax),
+			ops: []op{
+				{x: 1, wantEvicted: 1, wantOK: true},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for i, op := range tc.ops {
+				if got, ok := tc.b.Insert(op.x); got != op.wantEvicted || ok != op.wantOK {
+					t.Errorf("Operation #%d: Insert(%d) = %d, %t, want %d, %t", i, op.x, got, ok, op.wantEvicted, op.wantOK)
+				}
+				if got := tc.b.Len(); got > tc.b.Cap() {
+					t.Errorf("Operation #%d: Len() = %d, which exceeds Cap() = %d", i, got, tc.b.Cap())
+				}
+			}
+			if diff := gcmp.Diff(tc.wantAll, slices.Collect(tc.b.All())); diff != "" {
+				t.Errorf("All() diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBoundedDelete(t *testing.T) {
+	b := NewBounded[int](2, EvictMax)
+	b.Insert(1)
+	b.Insert(2)
+	if !b.Delete(1) {
+		t.Errorf("Delete(1) = false, want true")
+	}
+	if _, ok := b.Insert(3); ok {
+		t.Errorf("After Delete(1), Insert(3) evicted an element")
+	}
+	if got, _ := b.Min(); got != 2 {
+		t.Errorf("Min() = %d, want 2", got)
+	}
+	if got, _ := b.Max(); got != 3 {
+		t.Errorf("Max() = %d, want 3", got)
+	}
+}
+
+func TestNewBoundedNegativeCapacity(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewBounded(-1) did not panic")
+		}
+	}()
+	NewBounded[int](-1, EvictMax)
+}
@@ -0,0 +1,147 @@
+package sorted
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"sync/atomic"
+)
+
+// FromSeq creates a new sorted set of T containing the elements of seq, using < for comparisons.
+func FromSeq[T cmp.Ordered](seq iter.Seq[T]) *Set[T] {
+	s := NewSet[T]()
+	for x := range seq {
+		s.Insert(x)
+	}
+	return s
+}
+
+// FromSeqFunc creates a new set of T containing the elements of seq, ordered according to cmp.
+// Of the elements that compare as equal, the first one is kept.
+func FromSeqFunc[T any](cmp func(T, T) int, seq iter.Seq[T]) *Set[T] {
+	s := NewSetFunc(cmp)
+	for x := range seq {
+		s.Insert(x)
+	}
+	return s
+}
+
+// Collect returns a newly allocated slice of the elements of the set in sorted order.
+func (s *Set[T]) Collect() []T {
+	return slices.AppendSeq(make([]T, 0, s.size), s.All())
+}
+
+// Equal reports whether a and b contain the same elements,
+// according to the comparator of a.
+func Equal[T any](a, b *Set[T]) bool {
+	if a.size != b.size {
+		return false
+	}
+	ai, ok := a.first()
+	bi, _ := b.first()
+	for ok {
+		if a.compare(ai.value, bi.value) != 0 {
+			return false
+		}
+		ai, ok = ai.Next()
+		bi, _ = bi.Next()
+	}
+	return true
+}
+
+// Clone returns a copy of the set with the same comparator. Takes O(n) time.
+// Open transactions are not copied.
+func (s *Set[T]) Clone() *Set[T] {
+	c := &Set[T]{finder: s.finder}
+	c.owner = newOwner(c)
+	c.tree = s.tree.clone(c.owner)
+	return c
+}
+
+// Clone returns a copy of the set with the same comparator and hash function.
+func (s *HashedSet[T]) Clone() *HashedSet[T] {
+	return &HashedSet[T]{s.clone()}
+}
+
+func (s *summedSet[T]) clone() summedSet[T] {
+	return summedSet[T]{s.set.Clone(), s.weigh}
+}
+
+// clone copies the tree, giving the new items to o.
+func (s *tree[T]) clone(o *owner[T]) tree[T] {
+	c := newTree[T]()
+	c.size = s.size
+	c.augment = s.augment
+	var copyItem func(si, parent *SetItem[T]) *SetItem[T]
+	copyItem = func(si, parent *SetItem[T]) *SetItem[T] {
+		if si.level == 0 {
+			return c.bottom
+		}
+		res := &SetItem[T]{
+			level:  si.level,
+			size:   si.size,
+			value:  si.value,
+			parent: parent,
+			owner:  o,
+		}
+		res.l = copyItem(si.l, res)
+		res.r = copyItem(si.r, res)
+		return res
+	}
+	c.root = copyItem(s.root, nil)
+	return c
+}
+
+// Fork returns a copy of the set in O(1) time, which shares the tree with s until one of them changes.
+// The first change to either set copies the tree for it in O(n) time.
+// Calls that leave the set as it was, like inserting an element that is already there, do not copy.
+// Reading does not change the sets, so s and its forks can be read concurrently.
+//
+// The items of a shared tree can be read, but they belong to no set,
+// so [SetItem.InsertNearby] and [SetItem.SetValue] panic on them.
+// This includes the items of s, whether they were obtained before or after the fork,
+// until s changes and gets its own tree.
+// [Set.GetOrInsert] copies the tree first, even on s, so the item it returns can be changed.
+func (s *Set[T]) Fork() *Set[T] {
+	s.owner.set.Store(nil)
+	return &Set[T]{
+		tree:   s.tree,
+		finder: s.finder,
+		owner:  s.owner,
+	}
+}
+
+// Fork returns a copy of the set which shares the tree with s until one of them changes.
+// See [Set.Fork].
+func (s *HashedSet[T]) Fork() *HashedSet[T] {
+	return &HashedSet[T]{s.fork()}
+}
+
+func (s *summedSet[T]) fork() summedSet[T] {
+	return summedSet[T]{s.set.Fork(), s.weigh}
+}
+
+// owner is the set that the items of a tree belong to.
+// Once forks share the tree, the items belong to none of them, and set is nil.
+type owner[T any] struct {
+	set atomic.Pointer[Set[T]]
+}
+
+func newOwner[T any](s *Set[T]) *owner[T] {
+	o := new(owner[T])
+	o.set.Store(s)
+	return o
+}
+
+// shared reports whether s shares its tree with forks.
+func (s *Set[T]) shared() bool {
+	return s.owner.set.Load() != s
+}
+
+// own makes sure that s does not share its tree with forks, so that it can be changed.
+func (s *Set[T]) own() {
+	if s.shared() {
+		s.owner = newOwner(s)
+		s.tree = s.tree.clone(s.owner)
+	}
+}
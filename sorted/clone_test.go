@@ -0,0 +1,212 @@
+package sorted
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"testing"
+
+	gcmp "github.com/google/go-cmp/cmp"
+)
+
+func TestClone(t *testing.T) {
+	for name, newSet := range map[string]func() *Set[int]{
+		"NewSet":     NewSet[int],
+		"NewSetFunc": func() *Set[int] { return NewSetFunc(cmp.Compare[int]) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			s := newSet()
+			for _, v := range permutation1[:1000] {
+				s.Insert(v)
+			}
+			c := s.Clone()
+			checkTree(t, &c.tree)
+			if !Equal(s, c) {
+				t.Fatalf("Clone() is not Equal to the original set")
+			}
+			c.Delete(permutation1[0])
+			c.Insert(-1)
+			if Equal(s, c) || Equal(c, s) {
+				t.Errorf("After changing the clone, it is still Equal to the original set")
+			}
+			if !s.Has(permutation1[0]) || s.Has(-1) {
+				t.Errorf("Changing the clone changed the original set")
+			}
+			si, _ := c.First()
+			if si, ok := si.InsertNearby(-2); !ok || si.set() != c {
+				t.Errorf("InsertNearby on an item of the clone did not insert to the clone")
+			}
+			checkTree(t, &c.tree)
+		})
+	}
+}
+
+func TestCollectFromSeq(t *testing.T) {
+	in := []int{3, 1, 2, 3}
+	want := []int{1, 2, 3}
+	for name, s := range map[string]*Set[int]{
+		"FromSeq":     FromSeq(slices.Values(in)),
+		"FromSeqFunc": FromSeqFunc(cmp.Compare[int], slices.Values(in)),
+	} {
+		if diff := gcmp.Diff(want, s.Collect()); diff != "" {
+			t.Errorf("%s(%v).Collect() diff (-want +got):\n%s", name, in, diff)
+		}
+	}
+	if got := NewSet[int]().Collect(); got == nil || len(got) != 0 {
+		t.Errorf("Collect() of an empty set = %#v, want an empty slice", got)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	for _, tc := range []struct {
+		a, b []int
+		want bool
+	}{
+		{want: true},
+		{a: []int{1}, b: []int{1}, want: true},
+		{a: []int{1, 2}, b: []int{2, 1}, want: true},
+		{a: []int{1}},
+		{a: []int{1}, b: []int{2}},
+		{a: []int{1, 2}, b: []int{1, 3}},
+	} {
+		a, b := FromSeq(slices.Values(tc.a)), FromSeq(slices.Values(tc.b))
+		if got := Equal(a, b); got != tc.want {
+			t.Errorf("Equal(%v, %v) = %t, want %t", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestFork(t *testing.T) {
+	s := FromSeq(slices.Values([]int{1, 2, 3}))
+	f := s.Fork()
+	g := f.Fork()
+	if f.root != s.root || g.root != s.root {
+		t.Fatalf("Forks do not share the tree with the original set")
+	}
+	if !f.Has(2) || f.Len() != 3 || !slices.Equal(f.Collect(), []int{1, 2, 3}) {
+		t.Errorf("Fork does not have the elements of the original set")
+	}
+	f.First()
+	f.FindGreaterThanOrEqual(2)
+	f.Insert(2)
+	f.Delete(4)
+	if f.root != s.root {
+		t.Errorf("Fork stopped sharing the tree without a change")
+	}
+
+	f.Insert(4)
+	if f.root == s.root {
+		t.Errorf("Fork still shares the tree after it has changed")
+	}
+	if g.root != s.root {
+		t.Errorf("Changing one fork unshared another")
+	}
+	s.Delete(1)
+	if g.root == s.root {
+		t.Errorf("Fork still shares the tree after the original set has changed")
+	}
+	for _, tc := range []struct {
+		name string
+		s    *Set[int]
+		want []int
+	}{
+		{"original", s, []int{2, 3}},
+		{"changed fork", f, []int{1, 2, 3, 4}},
+		{"unchanged fork", g, []int{1, 2, 3}},
+	} {
+		if diff := gcmp.Diff(tc.want, tc.s.Collect()); diff != "" {
+			t.Errorf("Collect() of %s diff (-want +got):\n%s", tc.name, diff)
+		}
+		checkTree(t, &tc.s.tree)
+	}
+	for _, tc := range []struct {
+		name string
+		s    *Set[int]
+	}{
+		{"original", s},
+		{"changed fork", f},
+	} {
+		if si, ok := tc.s.First(); !ok || si.set() != tc.s {
+			t.Errorf("First() of %s does not belong to it", tc.name)
+		}
+	}
+}
+
+func TestForkItems(t *testing.T) {
+	s := FromSeq(slices.Values([]int{1, 3}))
+	before, _ := s.First()
+	f := s.Fork()
+	shared, _ := f.First()
+	for name, change := range map[string]func(){
+		"InsertNearby on an item of a fork":                func() { shared.InsertNearby(2) },
+		"SetValue on an item of a fork":                    func() { shared.SetValue(1) },
+		"InsertNearby on an item obtained before the fork": func() { before.InsertNearby(2) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			change()
+		}()
+	}
+	if s.Has(2) || f.Has(2) {
+		t.Errorf("Changing an item of a shared tree changed a set")
+	}
+	si, _ := f.GetOrInsert(1)
+	si.InsertNearby(2)
+	if s.Has(2) {
+		t.Errorf("InsertNearby on an item of a fork changed the original set")
+	}
+	if !f.Has(2) {
+		t.Errorf("InsertNearby on an item from GetOrInsert of a fork did not change the fork")
+	}
+}
+
+func TestForkOriginalItems(t *testing.T) {
+	s := FromSeq(slices.Values([]int{1, 3}))
+	s.Fork()
+	first, _ := s.First()
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("SetValue on an item of the original set obtained after the fork did not panic")
+			}
+		}()
+		first.SetValue(1)
+	}()
+	s.Insert(2)
+	first, _ = s.First()
+	first.SetValue(1) // Must not panic, s has its own tree after the change.
+
+	s.Fork()
+	si, _ := s.GetOrInsert(3)
+	si.SetValue(3) // Must not panic, GetOrInsert copied the tree.
+	if !slices.Equal(slices.Collect(s.All()), []int{1, 2, 3}) {
+		t.Errorf("s = %v, want [1 2 3]", slices.Collect(s.All()))
+	}
+}
+
+func TestForkConcurrentReads(t *testing.T) {
+	s := FromSeq(slices.Values(permutation1[:1000]))
+	forks := []*Set[int]{s.Fork(), s.Fork()}
+	var wg sync.WaitGroup
+	for _, f := range forks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for x := range 1000 {
+				f.FindGreaterThanOrEqual(x)
+				f.First()
+				f.Last()
+			}
+		}()
+	}
+	wg.Wait()
+	for _, f := range forks {
+		if f.root != s.root {
+			t.Errorf("Reading from a fork copied the tree")
+		}
+	}
+}
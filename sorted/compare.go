@@ -0,0 +1,95 @@
+package sorted
+
+import (
+	"cmp"
+	"iter"
+)
+
+// The functions in this file build comparators that can be passed to NewSetFunc
+// and the other constructors that take one.
+// A comparator returns a negative number if a < b, a positive one if a > b, and 0 if they are equal.
+//
+// Subtracting the values, e.g. func(a, b int) int { return a - b },
+// is not a valid comparator, since the result may overflow.
+// Use cmp.Compare or ByKey instead.
+
+// Reverse returns a comparator that orders values in the opposite way to c.
+func Reverse[T any](c func(T, T) int) func(T, T) int {
+	return func(a, b T) int {
+		return c(b, a)
+	}
+}
+
+// ByKey returns a comparator that orders values by their keys, using cmp.Compare.
+func ByKey[T any, K cmp.Ordered](key func(T) K) func(T, T) int {
+	return func(a, b T) int {
+		return cmp.Compare(key(a), key(b))
+	}
+}
+
+// Then returns a comparator that orders values by the first of cs,
+// breaking ties by the second one and so on.
+func Then[T any](cs ...func(T, T) int) func(T, T) int {
+	return func(a, b T) int {
+		for _, c := range cs {
+			if r := c(a, b); r != 0 {
+				return r
+			}
+		}
+		return 0
+	}
+}
+
+// NilsFirst returns a comparator of pointers that orders nil before all other pointers,
+// and compares the values of non-nil pointers using c.
+func NilsFirst[T any](c func(T, T) int) func(*T, *T) int {
+	return func(a, b *T) int {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		case b == nil:
+			return 1
+		}
+		return c(*a, *b)
+	}
+}
+
+// NilsLast returns a comparator of pointers that orders nil after all other pointers,
+// and compares the values of non-nil pointers using c.
+func NilsLast[T any](c func(T, T) int) func(*T, *T) int {
+	return Reverse(NilsFirst(Reverse(c)))
+}
+
+// Lexicographic returns a comparator of slices that compares their elements using c,
+// in the same way as slices.CompareFunc:
+// by the first unequal element, and a shorter slice is less than a longer one with the same prefix.
+func Lexicographic[T any](c func(T, T) int) func([]T, []T) int {
+	return func(a, b []T) int {
+		for i := range min(len(a), len(b)) {
+			if r := c(a[i], b[i]); r != 0 {
+				return r
+			}
+		}
+		return cmp.Compare(len(a), len(b))
+	}
+}
+
+// Reversed returns a read-only view of s in the reverse order:
+// its Min is the Max of s, and All iterates like Backward of s.
+// The view reflects the later changes of s.
+func Reversed[T any](s Reader[T]) Reader[T] {
+	return reversed[T]{s}
+}
+
+type reversed[T any] struct {
+	s Reader[T]
+}
+
+func (r reversed[T]) Len() int              { return r.s.Len() }
+func (r reversed[T]) Has(x T) bool          { return r.s.Has(x) }
+func (r reversed[T]) Min() (T, bool)        { return r.s.Max() }
+func (r reversed[T]) Max() (T, bool)        { return r.s.Min() }
+func (r reversed[T]) All() iter.Seq[T]      { return r.s.Backward() }
+func (r reversed[T]) Backward() iter.Seq[T] { return r.s.All() }
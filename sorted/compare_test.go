@@ -0,0 +1,93 @@
+package sorted
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	gcmp "github.com/google/go-cmp/cmp"
+)
+
+func TestComparators(t *testing.T) {
+	type person struct {
+		name string
+		age  int
+	}
+	people := []person{{"b", 30}, {"a", 30}, {"c", 20}, {"a", 20}}
+	s := NewSetFunc(Then(ByKey(func(p person) int { return p.age }), Reverse(ByKey(func(p person) string { return p.name }))))
+	for _, p := range people {
+		s.Insert(p)
+	}
+	want := []person{{"c", 20}, {"a", 20}, {"b", 30}, {"a", 30}}
+	if diff := gcmp.Diff(want, s.Collect(), gcmp.AllowUnexported(person{})); diff != "" {
+		t.Errorf("Set ordered by age, then by name in reverse, diff (-want +got):\n%s", diff)
+	}
+	if got := Then[int]()(1, 2); got != 0 {
+		t.Errorf("Then() with no comparators returned %d, want 0", got)
+	}
+}
+
+func TestReverseOverflow(t *testing.T) {
+	c := Reverse(func(a, b int) int { return cmp.Compare(a, b) * math.MaxInt })
+	if got := c(1, 2); got <= 0 {
+		t.Errorf("Reverse(c)(1, 2) = %d, want positive", got)
+	}
+}
+
+func TestNils(t *testing.T) {
+	one, two := 1, 2
+	in := []*int{&two, nil, &one}
+	for _, tc := range []struct {
+		name string
+		c    func(*int, *int) int
+		want []*int
+	}{
+		{"NilsFirst", NilsFirst(cmp.Compare[int]), []*int{nil, &one, &two}},
+		{"NilsLast", NilsLast(cmp.Compare[int]), []*int{&one, &two, nil}},
+	} {
+		s := FromSeqFunc(tc.c, slices.Values(in))
+		if got := s.Collect(); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: Collect() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestLexicographic(t *testing.T) {
+	c := Lexicographic(strings.Compare)
+	for _, tc := range [][]string{
+		nil,
+		{"a"},
+		{"a", "b"},
+		{"a", "c"},
+		{"b"},
+	} {
+		for _, other := range [][]string{nil, {"a"}, {"a", "b"}, {"a", "c"}, {"b"}} {
+			if got, want := c(tc, other), slices.Compare(tc, other); got != want {
+				t.Errorf("Lexicographic(strings.Compare)(%q, %q) = %d, want %d", tc, other, got, want)
+			}
+		}
+	}
+}
+
+func TestReversed(t *testing.T) {
+	s := FromSeq(slices.Values([]int{1, 2, 3}))
+	r := Reversed[int](s)
+	if diff := gcmp.Diff([]int{3, 2, 1}, slices.Collect(r.All())); diff != "" {
+		t.Errorf("Reversed All() diff (-want +got):\n%s", diff)
+	}
+	s.Insert(4)
+	if diff := gcmp.Diff([]int{1, 2, 3, 4}, slices.Collect(r.Backward())); diff != "" {
+		t.Errorf("Reversed Backward() after Insert(4) diff (-want +got):\n%s", diff)
+	}
+	if got, _ := r.Min(); got != 4 {
+		t.Errorf("Reversed Min() = %d, want 4", got)
+	}
+	if got, _ := r.Max(); got != 1 {
+		t.Errorf("Reversed Max() = %d, want 1", got)
+	}
+	if !r.Has(2) || r.Len() != 4 {
+		t.Errorf("Reversed Has(2), Len() = %t, %d, want true, 4", r.Has(2), r.Len())
+	}
+}
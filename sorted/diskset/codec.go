@@ -0,0 +1,18 @@
+package diskset
+
+import "encoding/binary"
+
+// Uint64 is a Codec that encodes uint64 in 8 bytes in big-endian order.
+type Uint64 struct{}
+
+func (Uint64) Size() int                   { return 8 }
+func (Uint64) Encode(dst []byte, x uint64) { binary.BigEndian.PutUint64(dst, x) }
+func (Uint64) Decode(src []byte) uint64    { return binary.BigEndian.Uint64(src) }
+
+// Int64 is a Codec that encodes int64 in 8 bytes,
+// in big-endian order with the sign bit flipped, so that negative numbers come first.
+type Int64 struct{}
+
+func (Int64) Size() int                  { return 8 }
+func (Int64) Encode(dst []byte, x int64) { binary.BigEndian.PutUint64(dst, uint64(x)^1<<63) }
+func (Int64) Decode(src []byte) int64    { return int64(binary.BigEndian.Uint64(src) ^ 1<<63) }
@@ -0,0 +1,574 @@
+// Package diskset provides a sorted set that is stored in a file,
+// for sets that do not fit in memory.
+//
+// The set is a B+ tree of fixed-size pages.
+// Every change is first written to a write-ahead log next to the file,
+// so that an interrupted write is either completed or discarded
+// the next time the set is opened.
+package diskset
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"iter"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+)
+
+const (
+	pageSize = 4096
+	magic    = "algoBPT1"
+	// MaxKeySize is the largest supported Codec.Size.
+	MaxKeySize = 1024
+
+	leafHeader     = 24 // Kind, number of keys, previous and next leaf.
+	internalHeader = 8  // Kind and number of keys.
+
+	kindLeaf     = 1
+	kindInternal = 2
+
+	// walCommit marks the end of a complete log.
+	walCommit = ^uint64(0)
+)
+
+// Codec converts elements of T to and from fixed-size byte strings.
+// The encoding must preserve the order: if a < b, then the encoding of a
+// must be less than the encoding of b according to bytes.Compare.
+type Codec[T any] interface {
+	// Size is the length of every encoded element.
+	Size() int
+	// Encode writes x to dst, which has length Size().
+	Encode(dst []byte, x T)
+	// Decode reads an element from src, which has length Size().
+	Decode(src []byte) T
+}
+
+// Set is a sorted set of T stored in a file.
+// Elements are ordered by their encodings.
+// A Set must not be used by multiple goroutines or processes at once.
+type Set[T any] struct {
+	f, wal *os.File
+	codec  Codec[T]
+	meta
+	leafCap, internalCap int
+	batch                map[uint64]*node // Pages changed by the current operation.
+	err                  error            // Set if the file may be inconsistent with the memory.
+	afterLog             func() error     // Called after the log is written, for tests.
+}
+
+// meta is the content of the first page.
+type meta struct {
+	keySize int
+	root    uint64
+	pages   uint64
+	count   int
+}
+
+// node is a decoded page of the tree, except for the first one.
+type node struct {
+	id         uint64
+	leaf       bool
+	keys       [][]byte
+	children   []uint64 // len(keys)+1 children of an internal node.
+	prev, next uint64   // Neighbours of a leaf, or 0 if there is none.
+}
+
+// Open opens the set stored in the file at path, creating it if it does not exist.
+// The write-ahead log is stored in path + ".wal".
+// The codec must have the same Size as when the file was created.
+func Open[T any](path string, codec Codec[T]) (*Set[T], error) {
+	ks := codec.Size()
+	if ks <= 0 || ks > MaxKeySize {
+		return nil, fmt.Errorf("diskset: key size %d not in range [1, %d]", ks, MaxKeySize)
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	wal, err := os.OpenFile(path+".wal", os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	s := &Set[T]{
+		f:           f,
+		wal:         wal,
+		codec:       codec,
+		leafCap:     (pageSize - leafHeader) / ks,
+		internalCap: (pageSize - internalHeader - 8) / (ks + 8),
+		batch:       map[uint64]*node{},
+	}
+	if err := s.open(ks); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Set[T]) open(ks int) error {
+	if err := s.recover(); err != nil {
+		return err
+	}
+	info, err := s.f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() == 0 {
+		s.meta = meta{keySize: ks, root: 1, pages: 2}
+		s.write(&node{id: 1, leaf: true})
+		return s.commit()
+	}
+	page := make([]byte, pageSize)
+	if _, err := s.f.ReadAt(page, 0); err != nil {
+		return err
+	}
+	if string(page[:len(magic)]) != magic {
+		return errors.New("diskset: not a set file")
+	}
+	s.meta = meta{
+		keySize: int(binary.LittleEndian.Uint32(page[8:])),
+		root:    binary.LittleEndian.Uint64(page[16:]),
+		pages:   binary.LittleEndian.Uint64(page[24:]),
+		count:   int(binary.LittleEndian.Uint64(page[32:])),
+	}
+	if s.keySize != ks {
+		return fmt.Errorf("diskset: file has key size %d, codec has %d", s.keySize, ks)
+	}
+	return nil
+}
+
+// Close closes the files of the set.
+func (s *Set[T]) Close() error {
+	return errors.Join(s.f.Close(), s.wal.Close())
+}
+
+// Len returns the number of elements in the set.
+func (s *Set[T]) Len() int {
+	return s.count
+}
+
+// Has reports whether x is in the set.
+func (s *Set[T]) Has(x T) (bool, error) {
+	if s.err != nil {
+		return false, s.err
+	}
+	key := s.encodeKey(x)
+	n, _, err := s.findLeaf(key)
+	if err != nil {
+		return false, err
+	}
+	_, found := search(n.keys, key)
+	return found, nil
+}
+
+// Insert adds x to the set.
+// Returns whether the insertion happened, i.e.
+// returns false if x was already in the set, otherwise returns true.
+func (s *Set[T]) Insert(x T) (added bool, err error) {
+	if s.err != nil {
+		return false, s.err
+	}
+	key := s.encodeKey(x)
+	n, path, err := s.findLeaf(key)
+	if err != nil {
+		return false, err
+	}
+	i, found := search(n.keys, key)
+	if found {
+		return false, nil
+	}
+	n.keys = slices.Insert(n.keys, i, key)
+	s.count++
+	s.write(n)
+	for len(n.keys) > s.capacity(n) {
+		right, sep, err := s.split(n)
+		if err != nil {
+			return false, s.fail(err)
+		}
+		if len(path) == 0 {
+			root := &node{id: s.alloc(), keys: [][]byte{sep}, children: []uint64{n.id, right.id}}
+			s.root = root.id
+			s.write(root)
+			break
+		}
+		parent := path[len(path)-1]
+		path = path[:len(path)-1]
+		ci := upperBound(parent.keys, sep)
+		parent.keys = slices.Insert(parent.keys, ci, sep)
+		parent.children = slices.Insert(parent.children, ci+1, right.id)
+		s.write(parent)
+		n = parent
+	}
+	return true, s.commit()
+}
+
+// Delete removes x from the set if it exists.
+// The return value indicates whether the removal happened.
+//
+// Pages are not merged when elements are deleted,
+// and the space is only reused by elements that are inserted to the same page.
+func (s *Set[T]) Delete(x T) (deleted bool, err error) {
+	if s.err != nil {
+		return false, s.err
+	}
+	key := s.encodeKey(x)
+	n, _, err := s.findLeaf(key)
+	if err != nil {
+		return false, err
+	}
+	i, found := search(n.keys, key)
+	if !found {
+		return false, nil
+	}
+	n.keys = slices.Delete(n.keys, i, i+1)
+	s.count--
+	s.write(n)
+	return true, s.commit()
+}
+
+// Min returns the smallest value in the set and true.
+// If the set is empty returns false.
+func (s *Set[T]) Min() (x T, ok bool, err error) {
+	for x, err := range s.All() {
+		return x, err == nil, err
+	}
+	return x, false, nil
+}
+
+// Max returns the largest value in the set and true.
+// If the set is empty returns false.
+func (s *Set[T]) Max() (x T, ok bool, err error) {
+	for x, err := range s.Backward() {
+		return x, err == nil, err
+	}
+	return x, false, nil
+}
+
+// FindGreaterThanOrEqual returns the first element that is greater than or equal to x, and true.
+// If there is no such element, returns false.
+func (s *Set[T]) FindGreaterThanOrEqual(x T) (y T, ok bool, err error) {
+	for y, err := range s.Ascend(x) {
+		return y, err == nil, err
+	}
+	return y, false, nil
+}
+
+// All returns an iterator over all elements in the set in sorted order.
+// If reading fails, the iteration ends by yielding the error.
+func (s *Set[T]) All() iter.Seq2[T, error] {
+	return s.ascend(nil)
+}
+
+// Ascend returns an iterator over the elements that are greater than or equal to x, in sorted order.
+// If reading fails, the iteration ends by yielding the error.
+func (s *Set[T]) Ascend(x T) iter.Seq2[T, error] {
+	return s.ascend(s.encodeKey(x))
+}
+
+func (s *Set[T]) ascend(from []byte) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		if s.err != nil {
+			yield(zero, s.err)
+			return
+		}
+		n, _, err := s.findLeaf(from)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		i, _ := search(n.keys, from)
+		for {
+			for ; i < len(n.keys); i++ {
+				if !yield(s.codec.Decode(n.keys[i]), nil) {
+					return
+				}
+			}
+			if n.next == 0 {
+				return
+			}
+			if n, err = s.read(n.next); err != nil {
+				yield(zero, err)
+				return
+			}
+			i = 0
+		}
+	}
+}
+
+// Backward returns an iterator over all elements in the set in reverse order.
+// If reading fails, the iteration ends by yielding the error.
+func (s *Set[T]) Backward() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		if s.err != nil {
+			yield(zero, s.err)
+			return
+		}
+		n, err := s.read(s.root)
+		for err == nil && !n.leaf {
+			n, err = s.read(n.children[len(n.children)-1])
+		}
+		for err == nil {
+			for i := len(n.keys) - 1; i >= 0; i-- {
+				if !yield(s.codec.Decode(n.keys[i]), nil) {
+					return
+				}
+			}
+			if n.prev == 0 {
+				return
+			}
+			n, err = s.read(n.prev)
+		}
+		yield(zero, err)
+	}
+}
+
+func (s *Set[T]) encodeKey(x T) []byte {
+	b := make([]byte, s.keySize)
+	s.codec.Encode(b, x)
+	return b
+}
+
+// findLeaf returns the leaf where key belongs, and the internal nodes on the path to it.
+// A nil key leads to the first leaf.
+func (s *Set[T]) findLeaf(key []byte) (*node, []*node, error) {
+	var path []*node
+	n, err := s.read(s.root)
+	for err == nil && !n.leaf {
+		path = append(path, n)
+		i := 0
+		if key != nil {
+			i = upperBound(n.keys, key)
+		}
+		n, err = s.read(n.children[i])
+	}
+	return n, path, err
+}
+
+// search returns the index of the first key that is not less than key,
+// and whether it is equal to key.
+func search(keys [][]byte, key []byte) (int, bool) {
+	return slices.BinarySearchFunc(keys, key, bytes.Compare)
+}
+
+// upperBound returns the index of the first key that is greater than key.
+// In an internal node, that is the index of the child that may contain key.
+func upperBound(keys [][]byte, key []byte) int {
+	return sort.Search(len(keys), func(i int) bool { return bytes.Compare(keys[i], key) > 0 })
+}
+
+func (s *Set[T]) capacity(n *node) int {
+	if n.leaf {
+		return s.leafCap
+	}
+	return s.internalCap
+}
+
+// split moves the upper half of n to a new node,
+// and returns it with the smallest key that belongs to it.
+func (s *Set[T]) split(n *node) (*node, []byte, error) {
+	mid := len(n.keys) / 2
+	right := &node{id: s.alloc(), leaf: n.leaf}
+	var sep []byte
+	if n.leaf {
+		sep = n.keys[mid]
+		right.keys = slices.Clone(n.keys[mid:])
+		n.keys = n.keys[:mid:mid]
+		right.prev, right.next, n.next = n.id, n.next, right.id
+		if right.next != 0 {
+			next, err := s.read(right.next)
+			if err != nil {
+				return nil, nil, err
+			}
+			next.prev = right.id
+			s.write(next)
+		}
+	} else {
+		sep = n.keys[mid]
+		right.keys = slices.Clone(n.keys[mid+1:])
+		right.children = slices.Clone(n.children[mid+1:])
+		n.keys = n.keys[:mid:mid]
+		n.children = n.children[: mid+1 : mid+1]
+	}
+	s.write(n)
+	s.write(right)
+	return right, sep, nil
+}
+
+func (s *Set[T]) alloc() uint64 {
+	s.pages++
+	return s.pages - 1
+}
+
+// read returns the page with the given id, taking changes of the current operation into account.
+func (s *Set[T]) read(id uint64) (*node, error) {
+	if n, ok := s.batch[id]; ok {
+		return n, nil
+	}
+	page := make([]byte, pageSize)
+	if _, err := s.f.ReadAt(page, int64(id)*pageSize); err != nil {
+		return nil, fmt.Errorf("diskset: reading page %d: %w", id, err)
+	}
+	return s.decodePage(id, page)
+}
+
+// write adds the node to the current operation.
+func (s *Set[T]) write(n *node) {
+	s.batch[n.id] = n
+}
+
+// fail makes the set unusable after an error that may have left it inconsistent.
+func (s *Set[T]) fail(err error) error {
+	s.err = fmt.Errorf("diskset: set must be reopened after an error: %w", err)
+	return err
+}
+
+// commit writes the pages of the current operation and the metadata to the log,
+// then to the file, and then clears the log.
+func (s *Set[T]) commit() error {
+	ids := slices.Sorted(maps.Keys(s.batch))
+	var log bytes.Buffer
+	record := make([]byte, 8+pageSize)
+	binary.LittleEndian.PutUint64(record, 0)
+	s.encodeMeta(record[8:])
+	log.Write(record)
+	for _, id := range ids {
+		clear(record)
+		binary.LittleEndian.PutUint64(record, id)
+		s.encodePage(s.batch[id], record[8:])
+		log.Write(record)
+	}
+	log.Write(binary.LittleEndian.AppendUint64(nil, walCommit))
+	log.Write(binary.LittleEndian.AppendUint32(nil, crc32.ChecksumIEEE(log.Bytes())))
+	clear(s.batch)
+
+	if _, err := s.wal.WriteAt(log.Bytes(), 0); err != nil {
+		return s.fail(err)
+	}
+	if err := s.wal.Sync(); err != nil {
+		return s.fail(err)
+	}
+	if s.afterLog != nil {
+		if err := s.afterLog(); err != nil {
+			return s.fail(err)
+		}
+	}
+	if err := s.apply(log.Bytes()); err != nil {
+		return s.fail(err)
+	}
+	return nil
+}
+
+// apply writes the pages from a complete log to the file and clears the log.
+func (s *Set[T]) apply(log []byte) error {
+	for r := log; len(r) > 12; r = r[8+pageSize:] {
+		id := binary.LittleEndian.Uint64(r)
+		if _, err := s.f.WriteAt(r[8:8+pageSize], int64(id)*pageSize); err != nil {
+			return err
+		}
+	}
+	if err := s.f.Sync(); err != nil {
+		return err
+	}
+	if err := s.wal.Truncate(0); err != nil {
+		return err
+	}
+	return s.wal.Sync()
+}
+
+// recover completes the operation in the log if the log is complete,
+// and discards it otherwise.
+func (s *Set[T]) recover() error {
+	log, err := io.ReadAll(io.NewSectionReader(s.wal, 0, 1<<62))
+	if err != nil {
+		return err
+	}
+	if !validLog(log) {
+		if err := s.wal.Truncate(0); err != nil {
+			return err
+		}
+		return s.wal.Sync()
+	}
+	return s.apply(log)
+}
+
+func validLog(log []byte) bool {
+	const trailer = 12
+	if len(log) < trailer || (len(log)-trailer)%(8+pageSize) != 0 {
+		return false
+	}
+	end := len(log) - 4
+	return binary.LittleEndian.Uint64(log[end-8:]) == walCommit &&
+		binary.LittleEndian.Uint32(log[end:]) == crc32.ChecksumIEEE(log[:end])
+}
+
+func (s *Set[T]) encodeMeta(page []byte) {
+	copy(page, magic)
+	binary.LittleEndian.PutUint32(page[8:], uint32(s.keySize))
+	binary.LittleEndian.PutUint64(page[16:], s.root)
+	binary.LittleEndian.PutUint64(page[24:], s.pages)
+	binary.LittleEndian.PutUint64(page[32:], uint64(s.count))
+}
+
+// Page layout:
+//
+//	leaf:     kind, _, n (uint16), _ (4 bytes), prev, next (uint64), n keys
+//	internal: kind, _, n (uint16), _ (4 bytes), internalCap+1 children (uint64), n keys
+func (s *Set[T]) encodePage(n *node, page []byte) {
+	kind := byte(kindInternal)
+	keys := page[internalHeader+8*(s.internalCap+1):]
+	if n.leaf {
+		kind = kindLeaf
+		binary.LittleEndian.PutUint64(page[8:], n.prev)
+		binary.LittleEndian.PutUint64(page[16:], n.next)
+		keys = page[leafHeader:]
+	} else {
+		for i, c := range n.children {
+			binary.LittleEndian.PutUint64(page[internalHeader+8*i:], c)
+		}
+	}
+	page[0] = kind
+	binary.LittleEndian.PutUint16(page[2:], uint16(len(n.keys)))
+	for i, k := range n.keys {
+		copy(keys[i*s.keySize:], k)
+	}
+}
+
+func (s *Set[T]) decodePage(id uint64, page []byte) (*node, error) {
+	n := &node{id: id}
+	count := int(binary.LittleEndian.Uint16(page[2:]))
+	var keys []byte
+	switch page[0] {
+	case kindLeaf:
+		n.leaf = true
+		n.prev = binary.LittleEndian.Uint64(page[8:])
+		n.next = binary.LittleEndian.Uint64(page[16:])
+		keys = page[leafHeader:]
+		if count > s.leafCap {
+			return nil, fmt.Errorf("diskset: page %d is corrupted", id)
+		}
+	case kindInternal:
+		if count > s.internalCap {
+			return nil, fmt.Errorf("diskset: page %d is corrupted", id)
+		}
+		n.children = make([]uint64, count+1)
+		for i := range n.children {
+			n.children[i] = binary.LittleEndian.Uint64(page[internalHeader+8*i:])
+		}
+		keys = page[internalHeader+8*(s.internalCap+1):]
+	default:
+		return nil, fmt.Errorf("diskset: page %d is corrupted", id)
+	}
+	n.keys = make([][]byte, count)
+	for i := range n.keys {
+		n.keys[i] = keys[i*s.keySize : (i+1)*s.keySize : (i+1)*s.keySize]
+	}
+	return n, nil
+}
@@ -0,0 +1,221 @@
+package diskset
+
+import (
+	"errors"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/mabu/algo/sorted"
+)
+
+// bytes16 is a Codec with a larger key, so that the tree gets deeper with fewer elements.
+type bytes16 struct{}
+
+func (bytes16) Size() int { return 16 * 16 }
+func (bytes16) Encode(dst []byte, x uint64) {
+	clear(dst)
+	Uint64{}.Encode(dst, x)
+}
+func (bytes16) Decode(src []byte) uint64 { return Uint64{}.Decode(src) }
+
+func collect[T any](t *testing.T, seq func(func(T, error) bool)) []T {
+	t.Helper()
+	var res []T
+	for x, err := range seq {
+		if err != nil {
+			t.Fatalf("Iteration failed: %v", err)
+		}
+		res = append(res, x)
+	}
+	return res
+}
+
+func check(t *testing.T, s *Set[uint64], want *sorted.Set[uint64]) {
+	t.Helper()
+	if got, want := s.Len(), want.Len(); got != want {
+		t.Errorf("Len() = %d, want %d", got, want)
+	}
+	if diff := cmp.Diff(want.Collect(), collect(t, s.All()), cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("All() diff (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(slices.Collect(want.Backward()), collect(t, s.Backward()), cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("Backward() diff (-want +got):\n%s", diff)
+	}
+	wantMin, wantMinOK := want.Min()
+	if got, ok, err := s.Min(); got != wantMin || ok != wantMinOK || err != nil {
+		t.Errorf("Min() = %d, %t, %v, want %d, %t, nil", got, ok, err, wantMin, wantMinOK)
+	}
+	wantMax, wantMaxOK := want.Max()
+	if got, ok, err := s.Max(); got != wantMax || ok != wantMaxOK || err != nil {
+		t.Errorf("Max() = %d, %t, %v, want %d, %t, nil", got, ok, err, wantMax, wantMaxOK)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	for name, codec := range map[string]Codec[uint64]{
+		"Uint64":  Uint64{},
+		"bytes16": bytes16{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "set")
+			s, err := Open(path, codec)
+			if err != nil {
+				t.Fatalf("Open(%q) failed: %v", path, err)
+			}
+			r := rand.New(rand.NewPCG(1, 2))
+			want := sorted.NewSet[uint64]()
+			for i := range 5000 {
+				x := r.Uint64N(3000)
+				if r.IntN(3) > 0 {
+					if got, err := s.Insert(x); got != want.Insert(x) || err != nil {
+						t.Fatalf("Operation #%d: Insert(%d) = %t, %v, want %t, nil", i, x, got, err, !got)
+					}
+				} else if got, err := s.Delete(x); got != want.Delete(x) || err != nil {
+					t.Fatalf("Operation #%d: Delete(%d) = %t, %v, want %t, nil", i, x, got, err, !got)
+				}
+				y := r.Uint64N(3000)
+				if got, err := s.Has(y); got != want.Has(y) || err != nil {
+					t.Fatalf("Operation #%d: Has(%d) = %t, %v, want %t, nil", i, y, got, err, !got)
+				}
+				wantGE, wantGEOK := uint64(0), false
+				if si, ok := want.FindGreaterThanOrEqual(y); ok {
+					wantGE, wantGEOK = si.Value(), true
+				}
+				if got, ok, err := s.FindGreaterThanOrEqual(y); got != wantGE || ok != wantGEOK || err != nil {
+					t.Fatalf("Operation #%d: FindGreaterThanOrEqual(%d) = %d, %t, %v, want %d, %t, nil", i, y, got, ok, err, wantGE, wantGEOK)
+				}
+			}
+			check(t, s, want)
+			if err := s.Close(); err != nil {
+				t.Fatalf("Close() failed: %v", err)
+			}
+
+			s, err = Open(path, codec)
+			if err != nil {
+				t.Fatalf("Reopening %q failed: %v", path, err)
+			}
+			defer s.Close()
+			check(t, s, want)
+		})
+	}
+}
+
+func TestInt64(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "set"), Int64{})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer s.Close()
+	in := []int64{5, -3, 0, -1 << 63, 1<<63 - 1, -7}
+	for _, x := range in {
+		if _, err := s.Insert(x); err != nil {
+			t.Fatalf("Insert(%d) failed: %v", x, err)
+		}
+	}
+	want := slices.Sorted(slices.Values(in))
+	if diff := cmp.Diff(want, collect(t, s.All())); diff != "" {
+		t.Errorf("All() diff (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int64{0, 5, 1<<63 - 1}, collect(t, s.Ascend(-2))); diff != "" {
+		t.Errorf("Ascend(-2) diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	errCrash := errors.New("crash")
+	for _, tc := range []struct {
+		name string
+		// corrupt changes the log left by the crash.
+		corrupt func(t *testing.T, wal string)
+		want    []uint64
+	}{
+		{
+			name:    "CompleteLog",
+			corrupt: func(*testing.T, string) {},
+			want:    []uint64{1, 2, 3},
+		},
+		{
+			name: "TornLog",
+			corrupt: func(t *testing.T, wal string) {
+				info, err := os.Stat(wal)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Truncate(wal, info.Size()-1); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: []uint64{1, 2},
+		},
+		{
+			name: "CorruptedLog",
+			corrupt: func(t *testing.T, wal string) {
+				f, err := os.OpenFile(wal, os.O_RDWR, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer f.Close()
+				if _, err := f.WriteAt([]byte{0xff}, 100); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: []uint64{1, 2},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "set")
+			s, err := Open(path, Uint64{})
+			if err != nil {
+				t.Fatalf("Open failed: %v", err)
+			}
+			s.Insert(1)
+			s.Insert(2)
+			s.afterLog = func() error { return errCrash }
+			if _, err := s.Insert(3); !errors.Is(err, errCrash) {
+				t.Fatalf("Insert(3) with a crash after writing the log returned %v, want %v", err, errCrash)
+			}
+			if _, err := s.Has(1); err == nil {
+				t.Errorf("Has(1) after a failed write succeeded, want an error")
+			}
+			s.Close()
+			tc.corrupt(t, path+".wal")
+
+			s, err = Open(path, Uint64{})
+			if err != nil {
+				t.Fatalf("Reopening after a crash failed: %v", err)
+			}
+			defer s.Close()
+			if diff := cmp.Diff(tc.want, collect(t, s.All())); diff != "" {
+				t.Errorf("All() after recovery diff (-want +got):\n%s", diff)
+			}
+			if got := s.Len(); got != len(tc.want) {
+				t.Errorf("Len() after recovery = %d, want %d", got, len(tc.want))
+			}
+		})
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "set")
+	s, err := Open(path, Uint64{})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	s.Close()
+	if _, err := Open(path, bytes16{}); err == nil {
+		t.Errorf("Opening a file with a codec of a different size succeeded")
+	}
+	other := filepath.Join(dir, "other")
+	if err := os.WriteFile(other, []byte("not a set"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(other, Uint64{}); err == nil {
+		t.Errorf("Opening a file that is not a set succeeded")
+	}
+}
@@ -0,0 +1,250 @@
+package sorted
+
+import (
+	"cmp"
+	"iter"
+)
+
+// HashedSet is a sorted set that keeps track of the hash of every subtree.
+// This allows to compare sets by their digests and to find their differences
+// without visiting the common elements.
+//
+// The hash of a set of elements is the sum of hashes of the individual elements,
+// which does not depend on the shape of the tree.
+// Therefore the hash function should be well distributed,
+// and it must be the same for all sets that are compared,
+// e.g. it may not use a random seed if the sets are in different processes.
+type HashedSet[T any] struct {
+	summedSet[T]
+}
+
+// NewHashedSet creates a new HashedSet of T, using < for comparisons.
+func NewHashedSet[T cmp.Ordered](hash func(T) uint64) *HashedSet[T] {
+	return NewHashedSetFunc(cmp.Compare[T], hash)
+}
+
+// NewHashedSetFunc creates a new HashedSet of T which is ordered according to cmp.
+//
+// Elements that compare as equal but have different hashes
+// are reported by [Diff] as changed.
+func NewHashedSetFunc[T any](cmp func(T, T) int, hash func(T) uint64) *HashedSet[T] {
+	return &HashedSet[T]{newSummedSet(cmp, hash)}
+}
+
+// Digest returns the hash of the whole set.
+// Sets with equal elements have equal digests.
+func (s *HashedSet[T]) Digest() uint64 {
+	return s.set.root.value.sum
+}
+
+// weighted is an element of a summedSet.
+type weighted[T any] struct {
+	value  T
+	weight uint64
+	sum    uint64 // Total weight of the subtree whose root holds this element.
+}
+
+func sumWeights[T any](t *SetItem[weighted[T]]) {
+	t.value.sum = t.l.value.sum + t.value.weight + t.r.value.sum
+}
+
+// summedSet is a Set in which every subtree knows the total weight of its elements.
+// It is the common part of HashedSet and WeightedSet.
+// Plain Sets do not keep the sums, so they do not pay for them.
+type summedSet[T any] struct {
+	set   *Set[weighted[T]]
+	weigh func(T) uint64
+}
+
+func newSummedSet[T any](cmp func(T, T) int, weigh func(T) uint64) summedSet[T] {
+	s := NewSetFunc(func(a, b weighted[T]) int { return cmp(a.value, b.value) })
+	s.augment = sumWeights[T]
+	return summedSet[T]{s, weigh}
+}
+
+func (s *summedSet[T]) wrap(x T) weighted[T] {
+	return weighted[T]{value: x, weight: s.weigh(x)}
+}
+
+// Len returns the number of elements in the set.
+func (s *summedSet[T]) Len() int {
+	return s.set.Len()
+}
+
+// Has reports whether x is in the set.
+func (s *summedSet[T]) Has(x T) bool {
+	return s.set.Has(weighted[T]{value: x})
+}
+
+// Min returns the smallest value in the set and true.
+// If the set is empty returns false.
+func (s *summedSet[T]) Min() (T, bool) {
+	w, ok := s.set.Min()
+	return w.value, ok
+}
+
+// Max returns the largest value in the set and true.
+// If the set is empty returns false.
+func (s *summedSet[T]) Max() (T, bool) {
+	w, ok := s.set.Max()
+	return w.value, ok
+}
+
+// All returns an iterator over all elements in the set in sorted order.
+func (s *summedSet[T]) All() iter.Seq[T] {
+	return values(s.set.All())
+}
+
+// Backward returns an iterator over all elements in the set in reverse order.
+func (s *summedSet[T]) Backward() iter.Seq[T] {
+	return values(s.set.Backward())
+}
+
+func values[T any](seq iter.Seq[weighted[T]]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for w := range seq {
+			if !yield(w.value) {
+				return
+			}
+		}
+	}
+}
+
+// Insert adds x to the set.
+// Returns whether the insertion happened, i.e.
+// returns false if an element that compares as equal to x was already in the set, otherwise returns true.
+func (s *summedSet[T]) Insert(x T) (added bool) {
+	return s.set.Insert(s.wrap(x))
+}
+
+// Delete removes x from the set if it exists.
+// The return value indicates whether the removal happened.
+func (s *summedSet[T]) Delete(x T) (deleted bool) {
+	return s.set.Delete(weighted[T]{value: x})
+}
+
+// Replace puts x in place of the element that compares as equal to x,
+// and returns the replaced value and true.
+// If there is no such element, adds x to the set and returns false.
+// See [Set.Replace].
+func (s *summedSet[T]) Replace(x T) (old T, replaced bool) {
+	w, replaced := s.set.Replace(s.wrap(x))
+	return w.value, replaced
+}
+
+// Change is an element that is in only one of the sets passed to [Diff].
+type Change[T any] struct {
+	Value T
+	// Added reports whether the element is in the second set but not in the first one.
+	// Otherwise it was removed, i.e. it is in the first set only.
+	Added bool
+}
+
+// Diff returns an iterator over the changes that turn a into b, in sorted order.
+// An element that compares as equal in both sets, but has a different hash,
+// is reported as removed and then added.
+//
+// Parts of the sets that have the same number of elements and the same hash
+// are assumed to be equal, so the iteration takes O(d log² n) time,
+// where d is the number of changes.
+// This means that a hash collision may hide a difference.
+//
+// Both sets must have the same comparator and the same hash function.
+func Diff[T any](a, b *HashedSet[T]) iter.Seq[Change[T]] {
+	return func(yield func(Change[T]) bool) {
+		all := span{n: b.set.size, sum: b.set.root.value.sum}
+		diff(a.set.root, b.set, all, yield)
+	}
+}
+
+// span is a range of consecutive elements of a set.
+type span struct {
+	first, n int    // The index of the first element and the number of elements.
+	before   uint64 // Total weight of the elements before the range.
+	sum      uint64 // Total weight of the elements in the range.
+}
+
+// cut splits the span before the element at index i, whose preceding elements weigh before in total.
+// If found is true, that element is in the span and weighs w, and it is in neither part.
+func (sp span) cut(i int, before uint64, found bool, w uint64) (l, r span) {
+	l = span{sp.first, i - sp.first, sp.before, before - sp.before}
+	if found {
+		i++
+		before += w
+	}
+	r = span{i, sp.first + sp.n - i, before, sp.before + sp.sum - before}
+	return l, r
+}
+
+// diff yields the changes that turn the subtree of a rooted at t into the elements of b in the span,
+// which are the elements between the neighbors of the subtree in the sorted order.
+// Returns false if the iteration has been stopped.
+//
+// The recursion follows the shape of a, so the sizes and sums of its parts are known,
+// and only b needs a walk from the root to find the matching spans.
+func diff[T any](t *SetItem[weighted[T]], b *Set[weighted[T]], sp span, yield func(Change[T]) bool) bool {
+	switch {
+	case t.size == sp.n && t.value.sum == sp.sum:
+		return true
+	case t.size == 0:
+		if sp.n == 0 {
+			return true
+		}
+		return yieldRange(b.at(sp.first), sp.n, true, yield)
+	case sp.n == 0:
+		first := t
+		for first.l.level > 0 {
+			first = first.l
+		}
+		return yieldRange(first, t.size, false, yield)
+	}
+	bi, i, before := rank(b, t.value)
+	found := bi != nil
+	var w uint64
+	if found {
+		w = bi.value.weight
+	}
+	l, r := sp.cut(i, before, found, w)
+	if !diff(t.l, b, l, yield) {
+		return false
+	}
+	if !found || w != t.value.weight {
+		if !yield(Change[T]{t.value.value, false}) {
+			return false
+		}
+	}
+	if found && w != t.value.weight {
+		if !yield(Change[T]{bi.value.value, true}) {
+			return false
+		}
+	}
+	return diff(t.r, b, r, yield)
+}
+
+func yieldRange[T any](si *SetItem[weighted[T]], n int, added bool, yield func(Change[T]) bool) bool {
+	for ; n > 0; n-- {
+		if !yield(Change[T]{si.value.value, added}) {
+			return false
+		}
+		si, _ = si.Next()
+	}
+	return true
+}
+
+// rank returns the number of elements of s that are less than x, and their total weight.
+// If s has an element that compares as equal to x, it also returns its item, otherwise nil.
+func rank[T any](s *Set[weighted[T]], x weighted[T]) (si *SetItem[weighted[T]], n int, before uint64) {
+	for t := s.root; t != s.bottom; {
+		switch c := s.compare(t.value, x); {
+		case c < 0:
+			n += t.l.size + 1
+			before += t.value.sum - t.r.value.sum
+			t = t.r
+		case c > 0:
+			t = t.l
+		default:
+			return t, n + t.l.size, before + t.l.value.sum
+		}
+	}
+	return nil, n, before
+}
@@ -0,0 +1,125 @@
+package sorted
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	gcmp "github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// mix is the finalizer of SplitMix64.
+func mix(x uint64) uint64 {
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+func hashInt(x int) uint64 {
+	return mix(uint64(x))
+}
+
+func TestDiff(t *testing.T) {
+	r := rand.New(rand.NewPCG(7, 8))
+	for range 200 {
+		a, b := NewHashedSet(hashInt), NewHashedSet(hashInt)
+		inA, inB := map[int]bool{}, map[int]bool{}
+		for range r.IntN(300) {
+			x := r.IntN(400)
+			a.Insert(x)
+			inA[x] = true
+			if r.IntN(10) > 0 {
+				b.Insert(x)
+				inB[x] = true
+			}
+		}
+		for range r.IntN(10) {
+			x := r.IntN(400)
+			b.Insert(x)
+			inB[x] = true
+		}
+		for range r.IntN(10) {
+			x := r.IntN(400)
+			a.Delete(x)
+			delete(inA, x)
+		}
+		checkTree(t, &a.set.tree)
+		checkTree(t, &b.set.tree)
+		var want []Change[int]
+		for x := range 400 {
+			if inA[x] != inB[x] {
+				want = append(want, Change[int]{x, inB[x]})
+			}
+		}
+		if diff := gcmp.Diff(want, slices.Collect(Diff(a, b)), cmpopts.EquateEmpty()); diff != "" {
+			t.Fatalf("Diff(%v, %v) diff (-want +got):\n%s", slices.Collect(a.All()), slices.Collect(b.All()), diff)
+		}
+		if got, want := a.Digest() == b.Digest(), len(want) == 0; got != want {
+			t.Errorf("a.Digest() == b.Digest() is %t, want %t, for %v and %v", got, want, slices.Collect(a.All()), slices.Collect(b.All()))
+		}
+	}
+}
+
+func TestDiffStop(t *testing.T) {
+	a, b := NewHashedSet(hashInt), NewHashedSet(hashInt)
+	for i := range 100 {
+		a.Insert(2 * i)
+		b.Insert(2*i + 1)
+	}
+	n := 0
+	for range Diff(a, b) {
+		n++
+		if n == 5 {
+			break
+		}
+	}
+	if n != 5 {
+		t.Errorf("Diff yielded %d changes until break, want 5", n)
+	}
+}
+
+func TestDiffChangedValue(t *testing.T) {
+	hash := func(r record) uint64 {
+		h := hashInt(r.key)
+		for _, c := range []byte(r.value) {
+			h = mix(h ^ uint64(c))
+		}
+		return h
+	}
+	a, b := NewHashedSetFunc(compareRecords, hash), NewHashedSetFunc(compareRecords, hash)
+	for i := range 10 {
+		a.Insert(record{i, "a"})
+		b.Insert(record{i, "a"})
+	}
+	if a.Digest() != b.Digest() {
+		t.Errorf("Sets with equal elements have different digests")
+	}
+	b.Replace(record{3, "b"})
+	want := []Change[record]{
+		{record{3, "a"}, false},
+		{record{3, "b"}, true},
+	}
+	if diff := gcmp.Diff(want, slices.Collect(Diff(a, b)), gcmp.AllowUnexported(record{})); diff != "" {
+		t.Errorf("Diff after Replace diff (-want +got):\n%s", diff)
+	}
+	checkTree(t, &b.set.tree)
+}
+
+func TestDiffEmpty(t *testing.T) {
+	empty, s := NewHashedSet(hashInt), NewHashedSet(hashInt)
+	for i := range 50 {
+		s.Insert(i)
+	}
+	var added, removed []Change[int]
+	for i := range 50 {
+		added = append(added, Change[int]{i, true})
+		removed = append(removed, Change[int]{i, false})
+	}
+	if diff := gcmp.Diff(added, slices.Collect(Diff(empty, s))); diff != "" {
+		t.Errorf("Diff(empty, s) diff (-want +got):\n%s", diff)
+	}
+	if diff := gcmp.Diff(removed, slices.Collect(Diff(s, empty))); diff != "" {
+		t.Errorf("Diff(s, empty) diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,193 @@
+package sorted
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// IntSet is a sorted set of integers from a bounded universe [0, n).
+//
+// It is represented as a hierarchy of bitsets, where every bit of a higher level
+// tells whether the corresponding word of the level below has any bits set.
+// Most operations take O(log₆₄ n) time, and the set takes about n/8 bytes
+// of memory regardless of how many elements it has,
+// which makes it a good fit for dense integer keys such as graph nodes.
+type IntSet struct {
+	levels   [][]uint64
+	universe int
+	size     int
+}
+
+// NewIntSet creates a new set that can hold integers from 0 to universe-1.
+// Panics if universe is negative.
+func NewIntSet(universe int) *IntSet {
+	if universe < 0 {
+		panic("sorted: negative IntSet universe")
+	}
+	s := &IntSet{universe: universe}
+	for n := universe; ; {
+		n = (n + 63) / 64
+		if n == 0 {
+			n = 1
+		}
+		s.levels = append(s.levels, make([]uint64, n))
+		if n == 1 {
+			return s
+		}
+	}
+}
+
+// Universe returns the number of integers the set can hold.
+func (s *IntSet) Universe() int {
+	return s.universe
+}
+
+// Len returns the number of elements in the set.
+func (s *IntSet) Len() int {
+	return s.size
+}
+
+// Has reports whether x is in the set.
+func (s *IntSet) Has(x int) bool {
+	return x >= 0 && x < s.universe && s.levels[0][x/64]&(1<<(x%64)) != 0
+}
+
+// Insert adds x to the set.
+// Returns whether the insertion happened, i.e. false if x was already in the set.
+// Panics if x is out of the universe.
+func (s *IntSet) Insert(x int) (added bool) {
+	if x < 0 || x >= s.universe {
+		panic("sorted: IntSet element out of range")
+	}
+	if s.Has(x) {
+		return false
+	}
+	s.size++
+	for _, level := range s.levels {
+		w := &level[x/64]
+		wasEmpty := *w == 0
+		*w |= 1 << (x % 64)
+		if !wasEmpty {
+			break
+		}
+		x /= 64
+	}
+	return true
+}
+
+// Delete removes x from the set if it exists.
+// The return value indicates whether the removal happened.
+func (s *IntSet) Delete(x int) (deleted bool) {
+	if !s.Has(x) {
+		return false
+	}
+	s.size--
+	for _, level := range s.levels {
+		w := &level[x/64]
+		*w &^= 1 << (x % 64)
+		if *w != 0 {
+			break
+		}
+		x /= 64
+	}
+	return true
+}
+
+// Min returns the smallest element in the set and true.
+// If the set is empty returns false.
+func (s *IntSet) Min() (int, bool) {
+	return s.FindGreaterThanOrEqual(0)
+}
+
+// Max returns the largest element in the set and true.
+// If the set is empty returns false.
+func (s *IntSet) Max() (int, bool) {
+	return s.FindLessThanOrEqual(s.universe - 1)
+}
+
+// FindGreaterThanOrEqual returns the smallest element that is greater than or equal to x, and true.
+// If there is no such element, returns false.
+func (s *IntSet) FindGreaterThanOrEqual(x int) (int, bool) {
+	if x < 0 {
+		x = 0
+	}
+	if x >= s.universe {
+		return 0, false
+	}
+	for i, level := range s.levels {
+		w := x / 64
+		if w >= len(level) {
+			return 0, false
+		}
+		if word := level[w] & (^uint64(0) << (x % 64)); word != 0 {
+			x = w*64 + bits.TrailingZeros64(word)
+			for i--; i >= 0; i-- {
+				x = x*64 + bits.TrailingZeros64(s.levels[i][x])
+			}
+			return x, true
+		}
+		x = w + 1
+	}
+	return 0, false
+}
+
+// FindLessThanOrEqual returns the largest element that is less than or equal to x, and true.
+// If there is no such element, returns false.
+func (s *IntSet) FindLessThanOrEqual(x int) (int, bool) {
+	if x >= s.universe {
+		x = s.universe - 1
+	}
+	for i, level := range s.levels {
+		if x < 0 {
+			return 0, false
+		}
+		w := x / 64
+		if word := level[w] & (^uint64(0) >> (63 - x%64)); word != 0 {
+			x = w*64 + 63 - bits.LeadingZeros64(word)
+			for i--; i >= 0; i-- {
+				x = x*64 + 63 - bits.LeadingZeros64(s.levels[i][x])
+			}
+			return x, true
+		}
+		x = w - 1
+	}
+	return 0, false
+}
+
+// Next returns the smallest element that is greater than x, and true.
+// If there is no such element, returns false.
+func (s *IntSet) Next(x int) (int, bool) {
+	if x >= s.universe {
+		return 0, false
+	}
+	return s.FindGreaterThanOrEqual(x + 1)
+}
+
+// Prev returns the largest element that is less than x, and true.
+// If there is no such element, returns false.
+func (s *IntSet) Prev(x int) (int, bool) {
+	if x <= 0 {
+		return 0, false
+	}
+	return s.FindLessThanOrEqual(x - 1)
+}
+
+// All returns an iterator over all elements in the set in sorted order.
+func (s *IntSet) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		x, ok := s.Min()
+		for ok && yield(x) {
+			x, ok = s.Next(x)
+		}
+	}
+}
+
+// Backward returns an iterator over all elements in the set in reverse order.
+func (s *IntSet) Backward() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		x, ok := s.Max()
+		for ok && yield(x) {
+			x, ok = s.Prev(x)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package sorted
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	gcmp "github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestIntSetRandom(t *testing.T) {
+	r := rand.New(rand.NewPCG(9, 10))
+	for _, universe := range []int{0, 1, 63, 64, 65, 4095, 4096, 4097, 300000} {
+		s := NewIntSet(universe)
+		want := NewSet[int]()
+		for op := range 3000 {
+			x := r.IntN(universe+2) - 1
+			if r.IntN(3) > 0 && x >= 0 && x < universe {
+				if got, want := s.Insert(x), want.Insert(x); got != want {
+					t.Fatalf("Universe %d, operation #%d: Insert(%d) = %t, want %t", universe, op, x, got, want)
+				}
+			} else if got, want := s.Delete(x), want.Delete(x); got != want {
+				t.Fatalf("Universe %d, operation #%d: Delete(%d) = %t, want %t", universe, op, x, got, want)
+			}
+			if got, want := s.Has(x), want.Has(x); got != want {
+				t.Fatalf("Universe %d, operation #%d: Has(%d) = %t, want %t", universe, op, x, got, want)
+			}
+			wantGE, wantGEOK := 0, false
+			if si, ok := want.FindGreaterThanOrEqual(x); ok {
+				wantGE, wantGEOK = si.Value(), true
+			}
+			if got, ok := s.FindGreaterThanOrEqual(x); got != wantGE || ok != wantGEOK {
+				t.Fatalf("Universe %d, operation #%d: FindGreaterThanOrEqual(%d) = %d, %t, want %d, %t", universe, op, x, got, ok, wantGE, wantGEOK)
+			}
+			wantPrev, wantPrevOK := 0, false
+			for v := range want.Backward() {
+				if v < x {
+					wantPrev, wantPrevOK = v, true
+					break
+				}
+			}
+			if got, ok := s.Prev(x); got != wantPrev || ok != wantPrevOK {
+				t.Fatalf("Universe %d, operation #%d: Prev(%d) = %d, %t, want %d, %t", universe, op, x, got, ok, wantPrev, wantPrevOK)
+			}
+		}
+		if got, want := s.Len(), want.Len(); got != want {
+			t.Errorf("Universe %d: Len() = %d, want %d", universe, got, want)
+		}
+		if diff := gcmp.Diff(slices.Collect(want.All()), slices.Collect(s.All()), cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("Universe %d: All() diff (-want +got):\n%s", universe, diff)
+		}
+		if diff := gcmp.Diff(slices.Collect(want.Backward()), slices.Collect(s.Backward()), cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("Universe %d: Backward() diff (-want +got):\n%s", universe, diff)
+		}
+		gotMin, gotMinOK := s.Min()
+		wantMin, wantMinOK := want.Min()
+		gotMax, gotMaxOK := s.Max()
+		wantMax, wantMaxOK := want.Max()
+		if gotMin != wantMin || gotMinOK != wantMinOK || gotMax != wantMax || gotMaxOK != wantMaxOK {
+			t.Errorf("Universe %d: Min(), Max() = %d, %t, %d, %t, want %d, %t, %d, %t",
+				universe, gotMin, gotMinOK, gotMax, gotMaxOK, wantMin, wantMinOK, wantMax, wantMaxOK)
+		}
+	}
+}
+
+func TestIntSetOutOfRange(t *testing.T) {
+	s := NewIntSet(10)
+	if s.Has(-1) || s.Has(10) || s.Delete(10) {
+		t.Errorf("Out of range elements are reported as present")
+	}
+	if _, ok := s.Next(10); ok {
+		t.Errorf("Next(10) in an empty set returned true")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Insert(10) into a set with universe 10 did not panic")
+		}
+	}()
+	s.Insert(10)
+}
@@ -0,0 +1,35 @@
+package sorted
+
+import "iter"
+
+// Reader is the read-only interface of a sorted set,
+// which allows code to accept any of the set implementations.
+type Reader[T any] interface {
+	// Len returns the number of elements in the set.
+	Len() int
+	// Has reports whether x is in the set.
+	Has(x T) bool
+	// Min returns the smallest value in the set and true.
+	// If the set is empty returns false.
+	Min() (T, bool)
+	// Max returns the largest value in the set and true.
+	// If the set is empty returns false.
+	Max() (T, bool)
+	// All returns an iterator over all elements in the set in sorted order.
+	All() iter.Seq[T]
+	// Backward returns an iterator over all elements in the set in reverse order.
+	Backward() iter.Seq[T]
+}
+
+// Ordered is the interface of a sorted set that can be changed.
+// It is implemented by Set, SliceSet, HashedSet and, for int, IntSet.
+// Package sortedtest contains tests that implementations should pass.
+type Ordered[T any] interface {
+	Reader[T]
+	// Insert adds x to the set.
+	// Returns false if an element that compares as equal to x was already in the set, otherwise returns true.
+	Insert(x T) (added bool)
+	// Delete removes x from the set if it exists.
+	// The return value indicates whether the removal happened.
+	Delete(x T) (deleted bool)
+}
@@ -0,0 +1,31 @@
+package sorted_test
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/mabu/algo/sorted"
+	"github.com/mabu/algo/sorted/sortedtest"
+)
+
+func TestOrdered(t *testing.T) {
+	for name, newSet := range map[string]func() sorted.Ordered[int]{
+		"NewSet":          func() sorted.Ordered[int] { return sorted.NewSet[int]() },
+		"NewSetFunc":      func() sorted.Ordered[int] { return sorted.NewSetFunc(cmp.Compare[int]) },
+		"NewSliceSet":     func() sorted.Ordered[int] { return sorted.NewSliceSet[int]() },
+		"NewSliceSetFunc": func() sorted.Ordered[int] { return sorted.NewSliceSetFunc(cmp.Compare[int]) },
+		"NewHashedSet": func() sorted.Ordered[int] {
+			return sorted.NewHashedSet(func(x int) uint64 { return uint64(x) * 0x9e3779b97f4a7c15 })
+		},
+		"NewIntSet": func() sorted.Ordered[int] { return sorted.NewIntSet(sortedtest.Universe) },
+		"Fork": func() sorted.Ordered[int] {
+			s := sorted.NewSet[int]()
+			s.Fork()
+			return s.Fork()
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			sortedtest.Run(t, newSet)
+		})
+	}
+}
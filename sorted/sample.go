@@ -0,0 +1,111 @@
+package sorted
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+)
+
+// Sample returns an element of the set chosen uniformly at random, and true.
+// If the set is empty returns false.
+// Takes O(log n) time.
+func (s *Set[T]) Sample(r *rand.Rand) (T, bool) {
+	if s.size == 0 {
+		var v T
+		return v, false
+	}
+	return s.at(r.IntN(s.size)).value, true
+}
+
+// SampleN returns n distinct elements of the set in sorted order,
+// chosen so that every subset of size n is equally likely, as with reservoir sampling.
+// If n >= Len(), returns all elements.
+// Takes O(n log n) time, regardless of the size of the set.
+// Panics if n is negative.
+func (s *Set[T]) SampleN(r *rand.Rand, n int) []T {
+	if n < 0 {
+		panic("sorted: negative SampleN count")
+	}
+	if n >= s.size {
+		return s.Collect()
+	}
+	// Floyd's algorithm picks n distinct indices from [0, size).
+	picked := make(map[int]bool, n)
+	for j := s.size - n; j < s.size; j++ {
+		i := r.IntN(j + 1)
+		if picked[i] {
+			i = j
+		}
+		picked[i] = true
+	}
+	indices := make([]int, 0, n)
+	for i := range picked {
+		indices = append(indices, i)
+	}
+	slices.Sort(indices)
+	res := make([]T, len(indices))
+	for k, i := range indices {
+		res[k] = s.at(i).value
+	}
+	return res
+}
+
+// WeightedSet is a sorted set whose elements have weights,
+// which allows to sample elements with probabilities proportional to their weights.
+//
+// The weight of an element is computed from its value,
+// so it can be changed with [WeightedSet.Replace]
+// when the comparator ignores the weight.
+// The total weight of the set must fit in uint64.
+type WeightedSet[T any] struct {
+	summedSet[T]
+}
+
+// NewWeightedSet creates a new WeightedSet of T, using < for comparisons.
+func NewWeightedSet[T cmp.Ordered](weight func(T) uint64) *WeightedSet[T] {
+	return NewWeightedSetFunc(cmp.Compare[T], weight)
+}
+
+// NewWeightedSetFunc creates a new WeightedSet of T which is ordered according to cmp.
+func NewWeightedSetFunc[T any](cmp func(T, T) int, weight func(T) uint64) *WeightedSet[T] {
+	return &WeightedSet[T]{newSummedSet(cmp, weight)}
+}
+
+// TotalWeight returns the sum of the weights of all elements.
+func (s *WeightedSet[T]) TotalWeight() uint64 {
+	return s.set.root.value.sum
+}
+
+// Sample returns an element of the set chosen with probability proportional to its weight, and true.
+// If the total weight is 0 returns false.
+// Takes O(log n) time.
+func (s *WeightedSet[T]) Sample(r *rand.Rand) (T, bool) {
+	if s.TotalWeight() == 0 {
+		var v T
+		return v, false
+	}
+	u := r.Uint64N(s.TotalWeight())
+	for t := s.set.root; ; {
+		if u < t.l.value.sum {
+			t = t.l
+			continue
+		}
+		u -= t.l.value.sum
+		if u < t.value.weight {
+			return t.value.value, true
+		}
+		u -= t.value.weight
+		t = t.r
+	}
+}
+
+// Clone returns a copy of the set with the same comparator and weight function.
+func (s *WeightedSet[T]) Clone() *WeightedSet[T] {
+	return &WeightedSet[T]{s.clone()}
+}
+
+// Fork returns a copy of the set which shares the tree with s until one of them changes.
+// See [Set.Fork].
+func (s *WeightedSet[T]) Fork() *WeightedSet[T] {
+	return &WeightedSet[T]{s.fork()}
+}
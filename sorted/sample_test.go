@@ -0,0 +1,126 @@
+package sorted
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// checkFrequencies verifies that every element was sampled about as often as expected.
+func checkFrequencies(t *testing.T, got map[int]int, want map[int]float64) {
+	t.Helper()
+	for x, w := range want {
+		if g := float64(got[x]); math.Abs(g-w) > 5*math.Sqrt(w)+1 {
+			t.Errorf("Sampled %d %d times, want about %.0f", x, got[x], w)
+		}
+	}
+	for x := range got {
+		if want[x] == 0 {
+			t.Errorf("Sampled %d, which should not be sampled", x)
+		}
+	}
+}
+
+func TestSample(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	s := NewSet[int]()
+	if got, ok := s.Sample(r); ok {
+		t.Errorf("Sample() on an empty set = %d, true, want false", got)
+	}
+	for i := range 10 {
+		s.Insert(i * i)
+	}
+	const n = 20000
+	got := map[int]int{}
+	for range n {
+		x, ok := s.Sample(r)
+		if !ok {
+			t.Fatalf("Sample() = %d, false, want true", x)
+		}
+		got[x]++
+	}
+	want := map[int]float64{}
+	for x := range s.All() {
+		want[x] = n / 10
+	}
+	checkFrequencies(t, got, want)
+}
+
+func TestSampleN(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	s := NewSet[int]()
+	for i := range 8 {
+		s.Insert(i)
+	}
+	if got, want := s.SampleN(r, 10), s.Collect(); !slices.Equal(got, want) {
+		t.Errorf("SampleN(10) of %v = %v, want all elements", want, got)
+	}
+	if got := s.SampleN(r, 0); got == nil || len(got) != 0 {
+		t.Errorf("SampleN(0) = %#v, want an empty slice", got)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("SampleN(-1) did not panic")
+			}
+		}()
+		s.SampleN(r, -1)
+	}()
+	const n = 20000
+	got := map[int]int{}
+	for range n {
+		sample := s.SampleN(r, 3)
+		if len(sample) != 3 || !slices.IsSorted(sample) || len(slices.Compact(slices.Clone(sample))) != 3 {
+			t.Fatalf("SampleN(3) = %v, want 3 distinct elements in sorted order", sample)
+		}
+		for _, x := range sample {
+			got[x]++
+		}
+	}
+	want := map[int]float64{}
+	for x := range s.All() {
+		want[x] = n * 3 / 8
+	}
+	checkFrequencies(t, got, want)
+}
+
+func TestWeightedSample(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	s := NewWeightedSetFunc(compareRecords, func(x record) uint64 { return uint64(len(x.value)) })
+	if got, ok := s.Sample(r); ok {
+		t.Errorf("Sample() on an empty set = %v, true, want false", got)
+	}
+	for i, v := range []string{"a", "", "bbbb", "cc", "", "ddddddd", "e"} {
+		s.Insert(record{i, v})
+	}
+	if got, ok := s.Sample(r); !ok {
+		t.Errorf("Sample() = %v, false, want true", got)
+	}
+	s.Replace(record{2, ""})
+	s.Replace(record{4, "ff"})
+	s.Delete(record{6, ""})
+	checkTree(t, &s.set.tree)
+	if got, want := s.TotalWeight(), uint64(1+2+2+7); got != want {
+		t.Errorf("TotalWeight() = %d, want %d", got, want)
+	}
+	const n = 24000
+	got := map[int]int{}
+	for range n {
+		x, ok := s.Sample(r)
+		if !ok {
+			t.Fatalf("Sample() = %v, false, want true", x)
+		}
+		got[x.key]++
+	}
+	checkFrequencies(t, got, map[int]float64{0: n / 12, 3: n / 6, 4: n / 6, 5: n * 7 / 12})
+
+	c := s.Clone()
+	c.Delete(record{5, ""})
+	if got, want := c.TotalWeight(), uint64(5); got != want {
+		t.Errorf("After deleting the heaviest element of a clone, TotalWeight() = %d, want %d", got, want)
+	}
+	if got, want := s.TotalWeight(), uint64(12); got != want {
+		t.Errorf("After changing a clone, TotalWeight() of the original = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,243 @@
+package sorted
+
+import "iter"
+
+// Sequence is a list of T that supports access, insertion and deletion
+// at any position in O(log n) time.
+//
+// Unlike Set, the elements are ordered by their positions rather than values,
+// so they do not need to be comparable and may repeat.
+type Sequence[T any] struct {
+	tree[T]
+}
+
+// NewSequence creates a new sequence containing xs in the given order.
+func NewSequence[T any](xs ...T) *Sequence[T] {
+	s := &Sequence[T]{newTree[T]()}
+	for _, x := range xs {
+		s.InsertAt(s.size, x)
+	}
+	return s
+}
+
+// Len returns the number of elements in the sequence.
+func (s *Sequence[T]) Len() int {
+	return s.size
+}
+
+// At returns the element at index i.
+// Panics if i is out of range.
+func (s *Sequence[T]) At(i int) T {
+	s.checkIndex(i, s.size-1)
+	return s.at(i).value
+}
+
+// Set replaces the element at index i with x.
+// Panics if i is out of range.
+func (s *Sequence[T]) Set(i int, x T) {
+	s.checkIndex(i, s.size-1)
+	s.at(i).value = x
+}
+
+// InsertAt inserts x at index i, shifting the elements at i and after it.
+// i may be equal to Len(), in which case x is appended.
+// Panics if i is out of range.
+func (s *Sequence[T]) InsertAt(i int, x T) {
+	s.checkIndex(i, s.size)
+	if s.root.level == 0 {
+		s.root = s.newItem(x, nil)
+		return
+	}
+	t := s.root
+	for {
+		if l := t.l.size; i <= l {
+			if t.l.level == 0 {
+				s.attach(s.newItem(x, t), &t.l)
+				return
+			}
+			t = t.l
+		} else {
+			i -= l + 1
+			if t.r.level == 0 {
+				s.attach(s.newItem(x, t), &t.r)
+				return
+			}
+			t = t.r
+		}
+	}
+}
+
+// DeleteAt removes the element at index i and returns it.
+// Panics if i is out of range.
+func (s *Sequence[T]) DeleteAt(i int) T {
+	s.checkIndex(i, s.size-1)
+	t := s.at(i)
+	x := t.value
+	s.remove(t)
+	return x
+}
+
+// Slice returns a newly allocated slice of the elements from index i to j-1.
+// Panics if the indices are out of range or j < i.
+func (s *Sequence[T]) Slice(i, j int) []T {
+	s.checkIndex(j, s.size)
+	s.checkIndex(i, j)
+	res := make([]T, 0, j-i)
+	if i == j {
+		return res
+	}
+	for t, ok := s.at(i), true; ok && len(res) < j-i; t, ok = t.Next() {
+		res = append(res, t.value)
+	}
+	return res
+}
+
+// Concat moves all elements of other to the end of s, leaving other empty.
+// Takes O(log(n+m)) time.
+// If other is s, appends a copy of the elements instead, which takes O(n) time.
+func (s *Sequence[T]) Concat(other *Sequence[T]) {
+	if other == s {
+		other = &Sequence[T]{s.tree.clone(nil)}
+	}
+	if other.size == 0 {
+		return
+	}
+	// The first element of other joins the two trees.
+	k := s.newItem(other.DeleteAt(0), nil)
+	s.root = s.join(s.root, k, other.root)
+	s.size += other.size
+	other.tree = newTree[T]()
+}
+
+// SplitAt removes the elements starting from index i from s
+// and returns them as a new sequence.
+// Takes O(log n) time.
+// Panics if i is out of range.
+func (s *Sequence[T]) SplitAt(i int) *Sequence[T] {
+	s.checkIndex(i, s.size)
+	l, r := s.divide(s.root, i)
+	res := &Sequence[T]{s.tree}
+	s.root, s.size = l, i
+	res.root, res.size = r, res.size-i
+	return res
+}
+
+// join returns the root of a tree with the items of the tree rooted at l, then k, then the items of r.
+// l and r are roots of valid trees, and k is not in either of them.
+// Takes O(|l.level - r.level| + 1) time.
+func (s *tree[T]) join(l, k, r *SetItem[T]) *SetItem[T] {
+	var t, p *SetItem[T] // The item whose place k takes, which becomes its child, and its parent.
+	switch {
+	case l.level == r.level:
+		k.l, k.r, k.parent, k.level = l, r, nil, l.level+1
+		adopt(k)
+		k.size = l.size + r.size + 1
+		s.fix(k)
+		return k
+	case l.level > r.level:
+		// The levels on the right spine of l decrease by at most 1 at a time,
+		// so it has an item on the level of r, or bottom if r is bottom.
+		for t = l; t.level > r.level; t = t.r {
+			p = t
+		}
+		k.l, k.r, k.parent = t, r, p
+		p.r = k
+	default:
+		// The levels on the left spine of r decrease by exactly 1 at a time.
+		for t = r; t.level > l.level; t = t.l {
+			p = t
+		}
+		k.l, k.r, k.parent = l, t, p
+		p.l = k
+	}
+	k.level = t.level + 1
+	adopt(k)
+	k.size = k.l.size + k.r.size + 1
+	s.fix(k)
+	// The path from k to the root is as long as the difference of the levels.
+	for t = k.parent; ; t = t.parent {
+		t.size = t.l.size + t.r.size + 1
+		s.fix(t)
+		t = s.skew(t)
+		t, _ = s.split(t)
+		if t.parent == nil {
+			return t
+		}
+	}
+}
+
+// adopt makes t the parent of its children.
+func adopt[T any](t *SetItem[T]) {
+	if t.l.level != 0 {
+		t.l.parent = t
+	}
+	if t.r.level != 0 {
+		t.r.parent = t
+	}
+}
+
+// divide splits the tree rooted at t into the trees with the first i items and the rest,
+// and returns their roots.
+// Takes O(log n) time, since the costs of the joins on the way up add up to the height of the tree.
+func (s *tree[T]) divide(t *SetItem[T], i int) (l, r *SetItem[T]) {
+	if t.level == 0 {
+		return t, t
+	}
+	tl, tr := t.l, t.r
+	if tl.level != 0 {
+		tl.parent = nil
+	}
+	if tr.level != 0 {
+		tr.parent = nil
+	}
+	if i <= tl.size {
+		l, r = s.divide(tl, i)
+		return l, s.join(r, t, tr)
+	}
+	l, r = s.divide(tr, i-tl.size-1)
+	return s.join(tl, t, l), r
+}
+
+// All returns an iterator over the indices and elements of the sequence in order.
+func (s *Sequence[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		if s.size == 0 {
+			return
+		}
+		i := 0
+		for t, ok := s.at(0), true; ok && yield(i, t.value); t, ok = t.Next() {
+			i++
+		}
+	}
+}
+
+// Backward returns an iterator over the indices and elements of the sequence
+// in reverse order.
+func (s *Sequence[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		if s.size == 0 {
+			return
+		}
+		i := s.size - 1
+		for t, ok := s.at(i), true; ok && yield(i, t.value); t, ok = t.Prev() {
+			i--
+		}
+	}
+}
+
+// Values returns an iterator over the elements of the sequence in order.
+func (s *Sequence[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, x := range s.All() {
+			if !yield(x) {
+				return
+			}
+		}
+	}
+}
+
+func (s *Sequence[T]) checkIndex(i, last int) {
+	if i < 0 || i > last {
+		panic("sorted: Sequence index out of range")
+	}
+}
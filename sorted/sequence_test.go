@@ -0,0 +1,163 @@
+package sorted
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	gcmp "github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestSequenceRandom(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	s := NewSequence[int]()
+	var want []int
+	for op := range 5000 {
+		switch n := len(want); {
+		case n == 0 || r.IntN(3) > 0:
+			i, x := r.IntN(n+1), r.Int()
+			s.InsertAt(i, x)
+			want = slices.Insert(want, i, x)
+		case r.IntN(2) == 0:
+			i := r.IntN(n)
+			if got := s.DeleteAt(i); got != want[i] {
+				t.Fatalf("Operation #%d: DeleteAt(%d) = %d, want %d", op, i, got, want[i])
+			}
+			want = slices.Delete(want, i, i+1)
+		default:
+			i, x := r.IntN(n), r.Int()
+			s.Set(i, x)
+			want[i] = x
+		}
+		checkTree(t, &s.tree)
+		if i := r.IntN(len(want) + 1); i < len(want) {
+			if got := s.At(i); got != want[i] {
+				t.Fatalf("Operation #%d: At(%d) = %d, want %d", op, i, got, want[i])
+			}
+		}
+	}
+	if diff := gcmp.Diff(want, slices.Collect(s.Values())); diff != "" {
+		t.Errorf("Values() diff (-want +got):\n%s", diff)
+	}
+	for i := range 20 {
+		j := i + r.IntN(len(want)-i)
+		if diff := gcmp.Diff(want[i:j], s.Slice(i, j)); diff != "" {
+			t.Errorf("Slice(%d, %d) diff (-want +got):\n%s", i, j, diff)
+		}
+	}
+}
+
+func TestSequenceIndices(t *testing.T) {
+	s := NewSequence("a", "b", "c")
+	var indices []int
+	var values []string
+	for i, v := range s.All() {
+		indices = append(indices, i)
+		values = append(values, v)
+	}
+	for i, v := range s.Backward() {
+		indices = append(indices, i)
+		values = append(values, v)
+	}
+	if diff := gcmp.Diff([]int{0, 1, 2, 2, 1, 0}, indices); diff != "" {
+		t.Errorf("All() and Backward() indices diff (-want +got):\n%s", diff)
+	}
+	if diff := gcmp.Diff([]string{"a", "b", "c", "c", "b", "a"}, values); diff != "" {
+		t.Errorf("All() and Backward() values diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestSequenceSplitConcat(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 7, 100} {
+		want := make([]int, n)
+		for i := range want {
+			want[i] = i
+		}
+		for i := 0; i <= n; i++ {
+			s := NewSequence(want...)
+			tail := s.SplitAt(i)
+			checkTree(t, &s.tree)
+			checkTree(t, &tail.tree)
+			if diff := gcmp.Diff(want[:i], slices.Collect(s.Values()), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("SplitAt(%d) of %d elements, head diff (-want +got):\n%s", i, n, diff)
+			}
+			if diff := gcmp.Diff(want[i:], slices.Collect(tail.Values()), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("SplitAt(%d) of %d elements, tail diff (-want +got):\n%s", i, n, diff)
+			}
+			s.Concat(tail)
+			checkTree(t, &s.tree)
+			if diff := gcmp.Diff(want, slices.Collect(s.Values()), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Concat after SplitAt(%d) of %d elements, diff (-want +got):\n%s", i, n, diff)
+			}
+			if got := tail.Len(); got != 0 {
+				t.Errorf("After Concat, the other sequence has Len() = %d, want 0", got)
+			}
+		}
+	}
+	s := NewSequence(1, 2)
+	s.Concat(NewSequence(3))
+	s.Concat(s)
+	checkTree(t, &s.tree)
+	if diff := gcmp.Diff([]int{1, 2, 3, 1, 2, 3}, slices.Collect(s.Values())); diff != "" {
+		t.Errorf("s.Concat(s) diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestSequenceConcatRandom(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	var want []int
+	s := NewSequence[int]()
+	for i := range 200 {
+		// Sequences built separately have trees of different heights and their own sentinels.
+		other := make([]int, r.IntN(1<<r.IntN(9)))
+		for j := range other {
+			other[j] = r.IntN(1000)
+		}
+		if r.IntN(2) == 0 {
+			s.Concat(NewSequence(other...))
+			want = append(want, other...)
+		} else {
+			o := NewSequence(other...)
+			o.Concat(s)
+			s = o
+			want = append(other, want...)
+		}
+		k := r.IntN(len(want) + 1)
+		tail := s.SplitAt(k)
+		checkTree(t, &s.tree)
+		checkTree(t, &tail.tree)
+		if r.IntN(2) == 0 && k < len(want) {
+			tail.DeleteAt(0)
+			want = slices.Delete(want, k, k+1)
+		}
+		s.InsertAt(s.Len(), i)
+		want = slices.Insert(want, k, i)
+		s.Concat(tail)
+		checkTree(t, &s.tree)
+		if got := slices.Collect(s.Values()); !slices.Equal(got, want) {
+			t.Fatalf("Step %d: diff (-want +got):\n%s", i, gcmp.Diff(want, got))
+		}
+	}
+}
+
+func TestSequenceOutOfRange(t *testing.T) {
+	s := NewSequence(1, 2, 3)
+	for name, f := range map[string]func(){
+		"At(3)":          func() { s.At(3) },
+		"At(-1)":         func() { s.At(-1) },
+		"InsertAt(4, 0)": func() { s.InsertAt(4, 0) },
+		"DeleteAt(3)":    func() { s.DeleteAt(3) },
+		"Slice(2, 1)":    func() { s.Slice(2, 1) },
+		"SplitAt(4)":     func() { s.SplitAt(4) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
@@ -10,8 +10,9 @@ import (
 type SetItem[T any] struct {
 	l, r, parent *SetItem[T]
 	level        int8
+	size         int // Number of items in the subtree.
 	value        T
-	set          *Set[T]
+	owner        *owner[T] // Nil in a Sequence.
 }
 
 // Value returns the item's value.
@@ -57,20 +58,87 @@ func (si *SetItem[T]) Prev() (*SetItem[T], bool) {
 //
 // Returns the SetItem whose value is x,
 // and a bool indicating whether this is a newly added item.
+// Panics if si was obtained from a set that shared its tree with forks, see [Set.Fork].
+// After f := s.Fork() this includes s itself: its items, even those obtained after the fork,
+// cannot be changed until s changes or copies its tree with [Set.GetOrInsert].
 func (si *SetItem[T]) InsertNearby(x T) (*SetItem[T], bool) {
-	return si.set.insertNearby(si, x)
+	s := si.set()
+	return s.insertNearby(s, si, x)
+}
+
+// SetValue replaces the item's value with x.
+// Panics if x does not compare as equal to the current value,
+// as that would break the order of the set,
+// or if si was obtained from a set that shared its tree with forks, see [Set.Fork].
+// After f := s.Fork() this includes s itself: its items, even those obtained after the fork,
+// cannot be changed until s changes or copies its tree with [Set.GetOrInsert].
+func (si *SetItem[T]) SetValue(x T) {
+	s := si.set()
+	if s.compare(si.value, x) != 0 {
+		panic("sorted: SetValue with a value that does not compare as equal")
+	}
+	s.setValue(si, x)
+}
+
+// set returns the set that si belongs to.
+func (si *SetItem[T]) set() *Set[T] {
+	s := si.owner.set.Load()
+	if s == nil {
+		panic("sorted: changing an item of a tree shared by forks")
+	}
+	return s
 }
 
 // Set is a sorted (ordered) set of T.
 type Set[T any] struct {
+	tree[T]
+	finder[T]
+	tx    *Tx[T] // The innermost open transaction.
+	undo  []undo[T]
+	owner *owner[T] // The owner of the items of the tree.
+}
+
+// tree is an AA tree whose nodes know the sizes of their subtrees.
+// It contains the balancing logic shared by Set and Sequence.
+//
+// The bottom sentinel is never written after it is created,
+// and the code shared with Sequence recognizes it by its level 0,
+// so trees that were joined together may have several sentinels.
+type tree[T any] struct {
 	root   *SetItem[T]
 	bottom *SetItem[T]
-	finder[T]
-	size int
+	size   int
+	// augment, if not nil, recomputes the subtree aggregate kept in the value of an item
+	// from the values of its children. It is called whenever the children change.
+	augment func(*SetItem[T])
+}
+
+func newTree[T any]() tree[T] {
+	bottom := new(SetItem[T])
+	bottom.l = bottom
+	bottom.r = bottom
+	return tree[T]{
+		root:   bottom,
+		bottom: bottom,
+	}
+}
+
+// rotate fixes the subtree sizes of t and its child c
+// for a rotation where c becomes the parent of t,
+// and g, which is a child of c, becomes a child of t.
+func rotate[T any](t, c, g *SetItem[T]) {
+	t.size, c.size = t.size-c.size+g.size, t.size
+}
+
+// fix recomputes the aggregate of t if the tree has one.
+func (s *tree[T]) fix(t *SetItem[T]) {
+	if s.augment != nil {
+		s.augment(t)
+	}
 }
 
-func (s *Set[T]) skew(t *SetItem[T]) *SetItem[T] {
-	if t.l.level == t.level {
+func (s *tree[T]) skew(t *SetItem[T]) *SetItem[T] {
+	if t.level != 0 && t.l.level == t.level {
 		if p := t.parent; p == nil {
 			s.root = t.l
 		} else {
@@ -80,12 +148,20 @@ func (s *Set[T]) skew(t *SetItem[T]) *SetItem[T] {
 				p.r = t.l
 			}
 		}
-		t.l, t.parent, t.l.r, t.l.parent, t.l.r.parent, t = t.l.r, t.l, t, t.parent, t, t.l
+		c, g := t.l, t.l.r
+		rotate(t, c, g)
+		t.l, t.parent, c.r, c.parent = g, c, t, t.parent
+		if g.level != 0 {
+			g.parent = t
+		}
+		s.fix(t)
+		s.fix(c)
+		t = c
 	}
 	return t
 }
 
-func (s *Set[T]) split(t *SetItem[T]) (*SetItem[T], bool) {
+func (s *tree[T]) split(t *SetItem[T]) (*SetItem[T], bool) {
 	if t.level == t.r.r.level {
 		if p := t.parent; p == nil {
 			s.root = t.r
@@ -96,7 +172,15 @@ func (s *Set[T]) split(t *SetItem[T]) (*SetItem[T], bool) {
 				p.r = t.r
 			}
 		}
-		t.r, t.parent, t.r.l, t.r.parent, t.r.l.parent, t = t.r.l, t.r, t, t.parent, t, t.r
+		c, g := t.r, t.r.l
+		rotate(t, c, g)
+		t.r, t.parent, c.l, c.parent = g, c, t, t.parent
+		if g.level != 0 {
+			g.parent = t
+		}
+		s.fix(t)
+		s.fix(c)
+		t = c
 		t.level++
 		return t, true
 	}
@@ -104,14 +188,12 @@ func (s *Set[T]) split(t *SetItem[T]) (*SetItem[T], bool) {
 }
 
 func newSet[T any](f finder[T]) *Set[T] {
-	bottom := new(SetItem[T])
-	bottom.l = bottom
-	bottom.r = bottom
-	return &Set[T]{
-		root:   bottom,
-		bottom: bottom,
+	s := &Set[T]{
+		tree:   newTree[T](),
 		finder: f,
 	}
+	s.owner = newOwner(s)
+	return s
 }
 
 // finder contains the methods that depend on the comparator.
@@ -128,7 +210,9 @@ type finder[T any] interface {
 
 	findGreaterThanOrEqual(root *SetItem[T], x T) (*SetItem[T], bool)
 
-	insertNearby(*SetItem[T], T) (*SetItem[T], bool)
+	insertNearby(s *Set[T], si *SetItem[T], x T) (*SetItem[T], bool)
+
+	compare(a, b T) int
 }
 
 // NewSet creates a new sorted set of T, using < for comparisons.
@@ -153,6 +237,10 @@ func (s *Set[T]) Len() int {
 
 // First returns the smallest SetItem and true, or nil and false if the set is empty.
 func (s *Set[T]) First() (*SetItem[T], bool) {
+	return s.first()
+}
+
+func (s *tree[T]) first() (*SetItem[T], bool) {
 	if s.root.level == 0 {
 		return nil, false
 	}
@@ -165,6 +253,10 @@ func (s *Set[T]) First() (*SetItem[T], bool) {
 
 // Last returns the largest SetItem and true, or nil and false if the set is empty.
 func (s *Set[T]) Last() (*SetItem[T], bool) {
+	return s.last()
+}
+
+func (s *tree[T]) last() (*SetItem[T], bool) {
 	if s.root.level == 0 {
 		return nil, false
 	}
@@ -178,7 +270,7 @@ func (s *Set[T]) Last() (*SetItem[T], bool) {
 // Min returns the smallest value in the set and true.
 // If the set is empty returns false.
 func (s *Set[T]) Min() (T, bool) {
-	si, ok := s.First()
+	si, ok := s.first()
 	if !ok {
 		var v T
 		return v, false
@@ -189,7 +281,7 @@ func (s *Set[T]) Min() (T, bool) {
 // Max returns the largest value in the set and true.
 // If the set is empty returns false.
 func (s *Set[T]) Max() (T, bool) {
-	si, ok := s.Last()
+	si, ok := s.last()
 	if !ok {
 		var v T
 		return v, false
@@ -200,7 +292,7 @@ func (s *Set[T]) Max() (T, bool) {
 // All returns an iterator over all elements in the set in sorted order.
 func (s *Set[T]) All() iter.Seq[T] {
 	return func(yield func(T) bool) {
-		si, ok := s.First()
+		si, ok := s.first()
 		for ok && yield(si.value) {
 			si, ok = si.Next()
 		}
@@ -210,7 +302,7 @@ func (s *Set[T]) All() iter.Seq[T] {
 // Backward returns an iterator over all elements in the set in reverse order.
 func (s *Set[T]) Backward() iter.Seq[T] {
 	return func(yield func(T) bool) {
-		si, ok := s.Last()
+		si, ok := s.last()
 		for ok && yield(si.value) {
 			si, ok = si.Prev()
 		}
@@ -221,6 +313,12 @@ func (s *Set[T]) Backward() iter.Seq[T] {
 // Returns whether the insertion happened, i.e.
 // returns false if an element that compares as equal to x was already in the set, otherwise returns true.
 func (s *Set[T]) Insert(x T) (added bool) {
+	if s.shared() {
+		if s.Has(x) {
+			return false
+		}
+		s.own()
+	}
 	if s.root == s.bottom {
 		s.root = s.newItem(x, nil)
 		return true
@@ -236,8 +334,19 @@ func (s *Set[T]) insert(root *SetItem[T], x T) (*SetItem[T], bool) {
 		return last, false
 	}
 	result := s.newItem(x, last)
-	*target = result
-	for t, n := last, 0; t != nil && n < 2; t = t.parent {
+	s.attach(result, target)
+	return result, true
+}
+
+// attach puts a new leaf item, whose parent is already set, to *target
+// and rebalances the tree.
+func (s *tree[T]) attach(item *SetItem[T], target **SetItem[T]) {
+	*target = item
+	for t := item.parent; t != nil; t = t.parent {
+		t.size++
+		s.fix(t)
+	}
+	for t, n := item.parent, 0; t != nil && n < 2; t = t.parent {
 		t = s.skew(t)
 		var ok bool
 		t, ok = s.split(t)
@@ -250,19 +359,84 @@ func (s *Set[T]) insert(root *SetItem[T], x T) (*SetItem[T], bool) {
 			s.root = t
 		}
 	}
-	return result, true
 }
 
 func (s *Set[T]) newItem(x T, parent *SetItem[T]) *SetItem[T] {
+	si := s.tree.newItem(x, parent)
+	si.owner = s.owner
+	s.log(undoInsert, x)
+	return si
+}
+
+func (s *tree[T]) newItem(x T, parent *SetItem[T]) *SetItem[T] {
 	s.size++
-	return &SetItem[T]{
+	si := &SetItem[T]{
 		value:  x,
 		level:  1,
+		size:   1,
 		l:      s.bottom,
 		r:      s.bottom,
 		parent: parent,
-		set:    s,
 	}
+	s.fix(si)
+	return si
+}
+
+// at returns the item at index i in the sorted order. 0 <= i < s.size.
+func (s *tree[T]) at(i int) *SetItem[T] {
+	t := s.root
+	for {
+		switch l := t.l.size; {
+		case i < l:
+			t = t.l
+		case i > l:
+			i -= l + 1
+			t = t.r
+		default:
+			return t
+		}
+	}
+}
+
+// GetOrInsert returns the SetItem whose value compares as equal to x and false.
+// If there is no such item, adds x to the set and returns the new SetItem and true.
+//
+// If s shares its tree with forks, it copies the tree even if x is found,
+// so that the returned item can be changed.
+// This takes O(n) time, and it also happens on the first call after s.Fork(),
+// because s shares its tree with the fork just as the fork does.
+func (s *Set[T]) GetOrInsert(x T) (*SetItem[T], bool) {
+	s.own()
+	if s.root == s.bottom {
+		s.root = s.newItem(x, nil)
+		return s.root, true
+	}
+	return s.insert(s.root, x)
+}
+
+// Replace puts x in place of the element that compares as equal to x,
+// and returns the replaced value and true.
+// If there is no such element, adds x to the set and returns false.
+//
+// This is useful with [NewSetFunc] when the comparator only looks at a part of T.
+func (s *Set[T]) Replace(x T) (old T, replaced bool) {
+	si, added := s.GetOrInsert(x)
+	if added {
+		return old, false
+	}
+	return s.setValue(si, x), true
+}
+
+// setValue replaces the value of si with x, which must compare as equal to it.
+func (s *Set[T]) setValue(si *SetItem[T], x T) (old T) {
+	old, si.value = si.value, x
+	if s.augment != nil {
+		for t := si; t != nil; t = t.parent {
+			s.augment(t)
+		}
+	}
+	s.log(undoSetValue, old)
+	return old
 }
 
 // Has reports whether x is in the set.
@@ -283,14 +457,43 @@ func (s *Set[T]) FindGreaterThanOrEqual(x T) (*SetItem[T], bool) {
 	return s.findGreaterThanOrEqual(s.root, x)
 }
 
+// prefix returns the number of elements that are less than x,
+// or less than or equal to x if inclusive is true.
+func (s *Set[T]) prefix(x T, inclusive bool) (n int) {
+	for t := s.root; t != s.bottom; {
+		if c := s.compare(t.value, x); c < 0 || inclusive && c == 0 {
+			n += t.l.size + 1
+			t = t.r
+		} else {
+			t = t.l
+		}
+	}
+	return n
+}
+
+// CountLess returns the number of elements that are less than x. Takes O(log n) time.
+func (s *Set[T]) CountLess(x T) int {
+	return s.prefix(x, false)
+}
+
+// CountRange returns the number of elements that are greater than or equal to lo and less than hi.
+// Takes O(log n) time.
+func (s *Set[T]) CountRange(lo, hi T) int {
+	if s.compare(lo, hi) >= 0 {
+		return 0
+	}
+	return s.CountLess(hi) - s.CountLess(lo)
+}
+
 // Delete removes x from the set if it exists.
 // The return value indicates whether the removal happened.
 func (s *Set[T]) Delete(x T) (deleted bool) {
-	defer func() {
-		if deleted {
-			s.size--
+	if s.shared() {
+		if !s.Has(x) {
+			return false
 		}
-	}()
+		s.own()
+	}
 	if s.root == s.bottom {
 		return false
 	}
@@ -298,26 +501,49 @@ func (s *Set[T]) Delete(x T) (deleted bool) {
 	if target != nil {
 		return false
 	}
+	s.log(undoDelete, last.value)
+	s.remove(last)
+	return true
+}
+
+// remove deletes the item from the tree.
+// Items that are higher than level 1 are not unlinked.
+// Instead, they take the value of their successor, which is unlinked.
+func (s *tree[T]) remove(last *SetItem[T]) {
+	s.size--
 	if last.level > 1 {
 		successor := last.r
-		for successor.l != s.bottom {
+		for successor.l.level != 0 {
 			successor = successor.l
 		}
 		last.value = successor.value
 		last = successor
 	} else if last.parent == nil {
 		s.root = last.r
-		s.root.parent = nil
-		return true
+		if s.root.level != 0 {
+			s.root.parent = nil
+		}
+		return
 	}
 	// Level 1, not root.
-	last.r.parent = last.parent
+	for t := last.parent; t != nil; t = t.parent {
+		t.size--
+	}
+	if last.r.level != 0 {
+		last.r.parent = last.parent
+	}
 	if last.parent.l == last {
 		last.parent.l = last.r
 	} else {
 		last.parent.r = last.r
 	}
 	last = last.parent
+	if s.augment != nil {
+		// This includes the item that took the successor's value.
+		for t := last; t != nil; t = t.parent {
+			s.augment(t)
+		}
+	}
 	for {
 		var ok bool
 		last, ok = s.decreaseLevel(last)
@@ -330,10 +556,9 @@ func (s *Set[T]) Delete(x T) (deleted bool) {
 		}
 		last = last.parent
 	}
-	return true
 }
 
-func (s *Set[T]) decreaseLevel(t *SetItem[T]) (*SetItem[T], bool) {
+func (s *tree[T]) decreaseLevel(t *SetItem[T]) (*SetItem[T], bool) {
 	if t.level > t.l.level+1 || t.level > t.r.level+1 {
 		t.level--
 		if t.r.level > t.level {
@@ -342,7 +567,9 @@ func (s *Set[T]) decreaseLevel(t *SetItem[T]) (*SetItem[T], bool) {
 
 		t = s.skew(t)
 		t.r = s.skew(t.r)
-		t.r.r = s.skew(t.r.r)
+		if t.r.level != 0 {
+			t.r.r = s.skew(t.r.r)
+		}
 		t, _ = s.split(t)
 		if t.r.level != 0 {
 			t.r, _ = s.split(t.r)
@@ -429,37 +656,37 @@ func (cmp setFunc[T]) findGreaterThanOrEqual(root *SetItem[T], x T) (*SetItem[T]
 	}
 }
 
-func (set[T]) insertNearby(si *SetItem[T], x T) (*SetItem[T], bool) {
+func (set[T]) insertNearby(s *Set[T], si *SetItem[T], x T) (*SetItem[T], bool) {
 	if si.value == x {
 		return si, false
 	}
 	if si.value < x {
 		next, ok := si.Next()
 		if !ok {
-			return si.set.insert(si, x)
+			return s.insert(si, x)
 		}
 		if next.value == x {
 			return next, false
 		}
 		if next.value > x {
-			return si.set.insert(lower(si, next), x)
+			return s.insert(lower(si, next), x)
 		}
 	} else {
 		prev, ok := si.Prev()
 		if !ok {
-			return si.set.insert(si, x)
+			return s.insert(si, x)
 		}
 		if prev.value == x {
 			return prev, false
 		}
 		if prev.value < x {
-			return si.set.insert(lower(si, prev), x)
+			return s.insert(lower(si, prev), x)
 		}
 	}
-	return si.set.insert(si.set.root, x)
+	return s.insert(s.root, x)
 }
 
-func (cmp setFunc[T]) insertNearby(si *SetItem[T], x T) (*SetItem[T], bool) {
+func (cmp setFunc[T]) insertNearby(s *Set[T], si *SetItem[T], x T) (*SetItem[T], bool) {
 	siCmp := cmp(si.value, x)
 	if siCmp == 0 {
 		return si, false
@@ -467,29 +694,37 @@ func (cmp setFunc[T]) insertNearby(si *SetItem[T], x T) (*SetItem[T], bool) {
 	if siCmp < 0 {
 		next, ok := si.Next()
 		if !ok {
-			return si.set.insert(si, x)
+			return s.insert(si, x)
 		}
 		nextCmp := cmp(next.value, x)
 		if nextCmp == 0 {
 			return next, false
 		}
 		if nextCmp > 0 {
-			return si.set.insert(lower(si, next), x)
+			return s.insert(lower(si, next), x)
 		}
 	} else {
 		prev, ok := si.Prev()
 		if !ok {
-			return si.set.insert(si, x)
+			return s.insert(si, x)
 		}
 		prevCmp := cmp(prev.value, x)
 		if prevCmp == 0 {
 			return prev, false
 		}
 		if prevCmp < 0 {
-			return si.set.insert(lower(si, prev), x)
+			return s.insert(lower(si, prev), x)
 		}
 	}
-	return si.set.insert(si.set.root, x)
+	return s.insert(s.root, x)
+}
+
+func (set[T]) compare(a, b T) int {
+	return cmp.Compare(a, b)
+}
+
+func (cmp setFunc[T]) compare(a, b T) int {
+	return cmp(a, b)
 }
 
 func lower[T any](a, b *SetItem[T]) *SetItem[T] {
@@ -7,7 +7,7 @@ import (
 
 func benchmarkSet() *Set[int] {
 	return NewSet[int]()
-	// return NewSetFunc(func(a, b int) int { return a - b })
+	// return NewSetFunc(cmp.Compare[int])
 }
 
 const n int = 1e5
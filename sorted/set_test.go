@@ -11,143 +11,6 @@ import (
 	gcmp "github.com/google/go-cmp/cmp"
 )
 
-func TestInsert(t *testing.T) {
-	for name, set := range map[string]*Set[int]{
-		"NewSet":     NewSet[int](),
-		"NewSetFunc": NewSetFunc(cmp.Compare[int]),
-	} {
-		t.Run(name, func(t *testing.T) {
-			operations := []struct {
-				x    int
-				want bool
-			}{
-				{1, true},
-				{1, false},
-				{2, true},
-				{3, true},
-				{2, false},
-				{3, false},
-			}
-			for i, op := range operations {
-				if got := set.Insert(op.x); got != op.want {
-					t.Errorf("Operation #%d: Insert(%d) = %t, want %t", i, op.x, got, op.want)
-				}
-			}
-		})
-	}
-}
-
-func TestDelete(t *testing.T) {
-	for name, newSet := range map[string]func() *Set[int]{
-		"NewSet":     NewSet[int],
-		"NewSetFunc": func() *Set[int] { return NewSetFunc(cmp.Compare[int]) },
-	} {
-		t.Run(name, func(t *testing.T) {
-			testCases := []struct {
-				insert  []int
-				delete  int
-				want    bool
-				wantAll []int
-			}{
-				{
-					delete: 42,
-				},
-				{
-					insert:  []int{1},
-					delete:  2,
-					wantAll: []int{1},
-				},
-				{
-					insert: []int{1},
-					delete: 1,
-					want:   true,
-				},
-				{
-					insert:  []int{1, 2},
-					delete:  1,
-					want:    true,
-					wantAll: []int{2},
-				},
-				{
-					insert:  []int{1, 2},
-					delete:  2,
-					want:    true,
-					wantAll: []int{1},
-				},
-				{
-					insert:  []int{1, 2},
-					delete:  3,
-					want:    false,
-					wantAll: []int{1, 2},
-				},
-				{
-					insert:  []int{2, 4, 6, 1, 7, 8},
-					delete:  3,
-					wantAll: []int{1, 2, 4, 6, 7, 8},
-				},
-				{
-					insert:  []int{2, 4, 6, 1, 7, 8},
-					delete:  1,
-					want:    true,
-					wantAll: []int{2, 4, 6, 7, 8},
-				},
-				{
-					insert:  []int{2, 4, 6, 1, 7, 8},
-					delete:  2,
-					want:    true,
-					wantAll: []int{1, 4, 6, 7, 8},
-				},
-				{
-					insert:  []int{2, 4, 6, 1, 7, 8},
-					delete:  4,
-					want:    true,
-					wantAll: []int{1, 2, 6, 7, 8},
-				},
-				{
-					insert:  []int{2, 4, 6, 1, 7, 8},
-					delete:  6,
-					want:    true,
-					wantAll: []int{1, 2, 4, 7, 8},
-				},
-				{
-					insert:  []int{2, 4, 6, 1, 7, 8},
-					delete:  7,
-					want:    true,
-					wantAll: []int{1, 2, 4, 6, 8},
-				},
-				{
-					insert:  []int{2, 4, 6, 1, 7, 8},
-					delete:  8,
-					want:    true,
-					wantAll: []int{1, 2, 4, 6, 7},
-				},
-				{
-					insert:  []int{2, 4, 6, 1, 7, 8},
-					delete:  9,
-					want:    false,
-					wantAll: []int{1, 2, 4, 6, 7, 8},
-				},
-			}
-
-			for _, tc := range testCases {
-				s := newSet()
-				for _, in := range tc.insert {
-					s.Insert(in)
-				}
-				if got := s.Delete(tc.delete); got != tc.want {
-					t.Errorf("After inserting %v, s.Delete(%d) = %t, want %t", tc.insert, tc.delete, got, tc.want)
-				}
-				if diff := gcmp.Diff(tc.wantAll, slices.Collect(s.All())); diff != "" {
-					t.Errorf("After inserting %v and performing s.Delete(%d), All() diff (-want +got):\n%s", tc.insert, tc.delete, diff)
-				}
-				if got, want := s.Len(), len(tc.wantAll); got != want {
-					t.Errorf("After inserting %v and performing s.Delete(%d): Len() = %d, want %d", tc.insert, tc.delete, got, want)
-				}
-			}
-		})
-	}
-}
-
 func TestFullToEmpty(t *testing.T) {
 	for name, newSet := range map[string]func() *Set[int]{
 		"NewSet":     NewSet[int],
@@ -191,6 +54,7 @@ func TestFullToEmpty(t *testing.T) {
 					if got, want := s.Len(), len(permutation1); got != want {
 						t.Errorf("After inserting %d elements to a set, Len() = %d, want %d", want, got, want)
 					}
+					checkTree(t, &s.tree)
 					for _, v := range permutation2 {
 						if !s.Delete(v) {
 							t.Errorf("While deleting permutation: Delete(%d) = false, want true", v)
@@ -199,161 +63,13 @@ func TestFullToEmpty(t *testing.T) {
 					if got := s.Len(); got != 0 {
 						t.Errorf("After inserting many elements and deleting them all, Len() = %d, want 0", got)
 					}
+					checkTree(t, &s.tree)
 				})
 			}
 		})
 	}
 }
 
-func TestHas(t *testing.T) {
-	for name, newSet := range map[string]func() *Set[int]{
-		"NewSet":     NewSet[int],
-		"NewSetFunc": func() *Set[int] { return NewSetFunc(cmp.Compare[int]) },
-	} {
-		t.Run(name, func(t *testing.T) {
-			testCases := []struct {
-				insert []int
-				delete []int
-				x      int
-				want   bool
-			}{
-				{
-					x: 10,
-				},
-				{
-					insert: []int{1},
-					x:      2,
-				},
-				{
-					insert: []int{1, 2},
-					x:      2,
-					want:   true,
-				},
-				{
-					insert: []int{1, 2},
-					x:      1,
-					want:   true,
-				},
-				{
-					insert: []int{1, 2},
-					x:      3,
-				},
-				{
-					insert: []int{1, 2},
-					x:      0,
-				},
-				{
-					insert: []int{2, 3},
-					x:      1,
-				},
-				{
-					insert: []int{1, 2},
-					delete: []int{1},
-					x:      2,
-					want:   true,
-				},
-				{
-					insert: []int{1, 2},
-					delete: []int{1},
-					x:      1,
-				},
-			}
-
-			for _, tc := range testCases {
-				s := newSet()
-				for _, in := range tc.insert {
-					s.Insert(in)
-				}
-				for _, de := range tc.delete {
-					s.Delete(de)
-				}
-				if got := s.Has(tc.x); got != tc.want {
-					t.Errorf("After inserting %v and deleting %v, s.Has(%d) = %t, want %t", tc.insert, tc.delete, tc.x, got, tc.want)
-				}
-			}
-		})
-	}
-}
-
-func TestAllMinMax(t *testing.T) {
-	for name, newSet := range map[string]func() *Set[int]{
-		"NewSet":     NewSet[int],
-		"NewSetFunc": func() *Set[int] { return NewSetFunc(cmp.Compare[int]) },
-	} {
-		t.Run(name, func(t *testing.T) {
-			testCases := []struct {
-				insert    []int
-				want      []int
-				wantMin   int
-				wantMinOK bool
-				wantMax   int
-				wantMaxOK bool
-			}{
-				{},
-				{
-					insert:    []int{1},
-					want:      []int{1},
-					wantMin:   1,
-					wantMinOK: true,
-					wantMax:   1,
-					wantMaxOK: true,
-				},
-				{
-					insert:    []int{1, 1},
-					want:      []int{1},
-					wantMin:   1,
-					wantMinOK: true,
-					wantMax:   1,
-					wantMaxOK: true,
-				},
-				{
-					insert:    []int{3, 2, 1},
-					want:      []int{1, 2, 3},
-					wantMin:   1,
-					wantMinOK: true,
-					wantMax:   3,
-					wantMaxOK: true,
-				},
-				{
-					insert:    []int{1, 2, 3, 2, 1},
-					want:      []int{1, 2, 3},
-					wantMin:   1,
-					wantMinOK: true,
-					wantMax:   3,
-					wantMaxOK: true,
-				},
-			}
-
-			for _, tc := range testCases {
-				s := newSet()
-				for _, in := range tc.insert {
-					s.Insert(in)
-				}
-				var got []int
-				for v := range s.All() {
-					got = append(got, v)
-				}
-				if !reflect.DeepEqual(got, tc.want) {
-					t.Errorf("After calling Insert with each of %v, iterating All() produced %v, want %v.",
-						tc.insert, got, tc.want)
-				}
-				if got, want := s.Len(), len(tc.want); got != want {
-					t.Errorf("After calling Insert with each of %v, Len() = %v, want %v.",
-						tc.insert, got, want)
-				}
-				if got, ok := s.Min(); got != tc.wantMin || ok != tc.wantMinOK {
-					t.Errorf("After calling Insert with each of %v, Min() = %v, %t, want %v, %t.",
-						tc.insert, got, ok, tc.wantMin, tc.wantMinOK)
-				}
-				if got, ok := s.Max(); got != tc.wantMax || ok != tc.wantMaxOK {
-					t.Errorf("After calling Insert with each of %v, Max() = %v, %t, want %v, %t.",
-						tc.insert, got, ok, tc.wantMax, tc.wantMaxOK)
-				}
-			}
-		})
-	}
-}
-
 type rangeTest struct {
 	name string
 	f    any
@@ -509,6 +225,50 @@ func TestFindGreaterThanOrEqual(t *testing.T) {
 	}
 }
 
+func TestCountRange(t *testing.T) {
+	for name, newSet := range map[string]func() *Set[int]{
+		"NewSet":     NewSet[int],
+		"NewSetFunc": func() *Set[int] { return NewSetFunc(cmp.Compare[int]) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			s := newSet()
+			in := []int{2, 4, 6, 1, 7, 8}
+			for _, x := range in {
+				s.Insert(x)
+			}
+			for _, tc := range []struct {
+				lo, hi int
+				want   int
+			}{
+				{0, 10, 6},
+				{1, 8, 5},
+				{1, 9, 6},
+				{3, 6, 1},
+				{3, 4, 0},
+				{4, 4, 0},
+				{8, 1, 0},
+				{9, 20, 0},
+				{-5, 1, 0},
+			} {
+				if got := s.CountRange(tc.lo, tc.hi); got != tc.want {
+					t.Errorf("After inserting %v, s.CountRange(%d, %d) = %d, want %d", in, tc.lo, tc.hi, got, tc.want)
+				}
+			}
+			for x := range 10 {
+				want := 0
+				for _, y := range in {
+					if y < x {
+						want++
+					}
+				}
+				if got := s.CountLess(x); got != want {
+					t.Errorf("After inserting %v, s.CountLess(%d) = %d, want %d", in, x, got, want)
+				}
+			}
+		})
+	}
+}
+
 func TestInsertNearby(t *testing.T) {
 	for name, newSet := range map[string]func() *Set[int]{
 		"NewSet":     NewSet[int],
@@ -641,6 +401,108 @@ func TestInsertNearby(t *testing.T) {
 	}
 }
 
+type record struct {
+	key   int
+	value string
+}
+
+func compareRecords(a, b record) int {
+	return cmp.Compare(a.key, b.key)
+}
+
+func TestReplace(t *testing.T) {
+	s := NewSetFunc(compareRecords)
+	if old, ok := s.Replace(record{1, "a"}); ok {
+		t.Errorf("Replace({1 a}) on an empty set = %v, true, want false", old)
+	}
+	if old, ok := s.Replace(record{1, "b"}); !ok || old != (record{1, "a"}) {
+		t.Errorf("Replace({1 b}) = %v, %t, want {1 a}, true", old, ok)
+	}
+	s.Insert(record{2, "c"})
+	want := []record{{1, "b"}, {2, "c"}}
+	if diff := gcmp.Diff(want, slices.Collect(s.All()), gcmp.AllowUnexported(record{})); diff != "" {
+		t.Errorf("After replacements, All() diff (-want +got):\n%s", diff)
+	}
+	if got := s.Len(); got != 2 {
+		t.Errorf("After replacements, Len() = %d, want 2", got)
+	}
+}
+
+func TestGetOrInsert(t *testing.T) {
+	s := NewSetFunc(compareRecords)
+	si, ok := s.GetOrInsert(record{1, "a"})
+	if !ok || si.Value() != (record{1, "a"}) {
+		t.Fatalf("GetOrInsert({1 a}) on an empty set = %v, %t, want {1 a}, true", si, ok)
+	}
+	got, ok := s.GetOrInsert(record{1, "b"})
+	if ok || got != si {
+		t.Errorf("GetOrInsert({1 b}) = %v, %t, want the existing item, false", got, ok)
+	}
+	got.SetValue(record{1, "c"})
+	if v, _ := s.Min(); v != (record{1, "c"}) {
+		t.Errorf("After SetValue({1 c}), Min() = %v, want {1 c}", v)
+	}
+	if _, ok := s.GetOrInsert(record{0, "d"}); !ok {
+		t.Errorf("GetOrInsert({0 d}) returned false, want true")
+	}
+	if got := s.Len(); got != 2 {
+		t.Errorf("Len() = %d, want 2", got)
+	}
+}
+
+func TestSetValuePanics(t *testing.T) {
+	s := NewSet[int]()
+	s.Insert(1)
+	si, _ := s.First()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("SetValue(2) on item 1 did not panic")
+		}
+	}()
+	si.SetValue(2)
+}
+
+// checkTree verifies the AA tree invariants, parent pointers and subtree aggregates.
+func checkTree[T any](t *testing.T, tr *tree[T]) {
+	t.Helper()
+	var check func(si *SetItem[T]) int
+	check = func(si *SetItem[T]) int {
+		if si.level == 0 {
+			// Joined trees may have several sentinels.
+			var zero T
+			if si.size != 0 || si.parent != nil || !reflect.DeepEqual(si.value, zero) {
+				t.Fatalf("Bottom has size %d, parent %v and value %v, want all zero", si.size, si.parent, si.value)
+			}
+			return 0
+		}
+		if tr.augment != nil {
+			// The children are checked below, so the aggregate computed from them is correct.
+			want := *si
+			tr.augment(&want)
+			if !reflect.DeepEqual(si.value, want.value) {
+				t.Fatalf("Item %v has a stale aggregate, want %v", si, want.value)
+			}
+		}
+		if si.l.level != 0 && si.l.parent != si || si.r.level != 0 && si.r.parent != si {
+			t.Fatalf("Wrong parent pointer below %v", si)
+		}
+		if si.l.level != si.level-1 || si.r.level != si.level && si.r.level != si.level-1 || si.r.r.level == si.level {
+			t.Fatalf("AA tree invariant violated at %v", si)
+		}
+		size := check(si.l) + check(si.r) + 1
+		if si.size != size {
+			t.Fatalf("Item %v has size %d, want %d", si, si.size, size)
+		}
+		return size
+	}
+	if tr.root.level != 0 && tr.root.parent != nil {
+		t.Fatalf("Root %v has a parent", tr.root)
+	}
+	if got := check(tr.root); got != tr.size {
+		t.Fatalf("Tree has %d items, but its size is %d", got, tr.size)
+	}
+}
+
 func (s *SetItem[T]) String() string {
 	if s == nil {
 		return "nil"
@@ -0,0 +1,130 @@
+package sorted
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// SliceSet is a sorted set of T backed by a sorted slice.
+// Queries use binary search, which is more cache-friendly than Set,
+// but changing the set takes O(n) time,
+// so it is best suited for sets that are built once and then only read.
+type SliceSet[T any] struct {
+	items []T
+	cmp   func(T, T) int
+}
+
+// NewSliceSet creates a new SliceSet containing xs, using cmp.Compare for comparisons.
+func NewSliceSet[T cmp.Ordered](xs ...T) *SliceSet[T] {
+	items := slices.Clone(xs)
+	slices.Sort(items)
+	return &SliceSet[T]{
+		items: slices.Compact(items),
+		cmp:   cmp.Compare[T],
+	}
+}
+
+// NewSliceSetFunc creates a new SliceSet containing xs, ordered according to cmp.
+// Of the elements that compare as equal, the first one is kept.
+func NewSliceSetFunc[T any](cmp func(T, T) int, xs ...T) *SliceSet[T] {
+	items := slices.Clone(xs)
+	slices.SortStableFunc(items, cmp)
+	return &SliceSet[T]{
+		items: slices.CompactFunc(items, func(a, b T) bool { return cmp(a, b) == 0 }),
+		cmp:   cmp,
+	}
+}
+
+// Freeze returns a SliceSet with the same elements and comparator as s.
+// Takes O(n) time.
+func (s *Set[T]) Freeze() *SliceSet[T] {
+	return &SliceSet[T]{
+		items: s.Collect(),
+		cmp:   s.compare,
+	}
+}
+
+// Len returns the number of elements in the set.
+func (s *SliceSet[T]) Len() int {
+	return len(s.items)
+}
+
+// At returns the element at index i in the sorted order.
+// Panics if i is out of range.
+func (s *SliceSet[T]) At(i int) T {
+	return s.items[i]
+}
+
+// Has reports whether x is in the set.
+func (s *SliceSet[T]) Has(x T) bool {
+	_, found := slices.BinarySearchFunc(s.items, x, s.cmp)
+	return found
+}
+
+// Min returns the smallest value in the set and true.
+// If the set is empty returns false.
+func (s *SliceSet[T]) Min() (T, bool) {
+	if len(s.items) == 0 {
+		var v T
+		return v, false
+	}
+	return s.items[0], true
+}
+
+// Max returns the largest value in the set and true.
+// If the set is empty returns false.
+func (s *SliceSet[T]) Max() (T, bool) {
+	if len(s.items) == 0 {
+		var v T
+		return v, false
+	}
+	return s.items[len(s.items)-1], true
+}
+
+// All returns an iterator over all elements in the set in sorted order.
+func (s *SliceSet[T]) All() iter.Seq[T] {
+	return slices.Values(s.items)
+}
+
+// Backward returns an iterator over all elements in the set in reverse order.
+func (s *SliceSet[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := len(s.items) - 1; i >= 0; i-- {
+			if !yield(s.items[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Insert adds x to the set. Takes O(n) time.
+// Returns whether the insertion happened, i.e.
+// returns false if an element that compares as equal to x was already in the set, otherwise returns true.
+func (s *SliceSet[T]) Insert(x T) (added bool) {
+	i, found := slices.BinarySearchFunc(s.items, x, s.cmp)
+	if found {
+		return false
+	}
+	s.items = slices.Insert(s.items, i, x)
+	return true
+}
+
+// Delete removes x from the set if it exists. Takes O(n) time.
+// The return value indicates whether the removal happened.
+func (s *SliceSet[T]) Delete(x T) (deleted bool) {
+	i, found := slices.BinarySearchFunc(s.items, x, s.cmp)
+	if !found {
+		return false
+	}
+	s.items = slices.Delete(s.items, i, i+1)
+	return true
+}
+
+// FindGreaterThanOrEqual returns the index of the first element that is greater than or equal to x, and true.
+// If there is no such element, returns false.
+// The element and the ones after it can be accessed by [SliceSet.At].
+func (s *SliceSet[T]) FindGreaterThanOrEqual(x T) (int, bool) {
+	i, _ := slices.BinarySearchFunc(s.items, x, s.cmp)
+	return i, i < len(s.items)
+}
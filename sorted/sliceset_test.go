@@ -0,0 +1,82 @@
+package sorted
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+
+	gcmp "github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+var (
+	_ Reader[int] = (*Set[int])(nil)
+	_ Reader[int] = (*SliceSet[int])(nil)
+	_ Reader[int] = (*Bounded[int])(nil)
+	_ Reader[int] = (*IntSet)(nil)
+	_ Reader[int] = (*HashedSet[int])(nil)
+)
+
+func TestSliceSet(t *testing.T) {
+	in := []int{5, 1, 3, 1, 9, 7, 3}
+	for name, s := range map[string]*SliceSet[int]{
+		"NewSliceSet":     NewSliceSet(in...),
+		"NewSliceSetFunc": NewSliceSetFunc(cmp.Compare[int], in...),
+		"Freeze":          FromSeq(slices.Values(in)).Freeze(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			want := []int{1, 3, 5, 7, 9}
+			if got := s.Len(); got != len(want) {
+				t.Errorf("Len() = %d, want %d", got, len(want))
+			}
+			if diff := gcmp.Diff(want, slices.Collect(s.All())); diff != "" {
+				t.Errorf("All() diff (-want +got):\n%s", diff)
+			}
+			slices.Reverse(want)
+			if diff := gcmp.Diff(want, slices.Collect(s.Backward())); diff != "" {
+				t.Errorf("Backward() diff (-want +got):\n%s", diff)
+			}
+			for x := range 11 {
+				if got, want := s.Has(x), x%2 == 1 && x < 10; got != want {
+					t.Errorf("Has(%d) = %t, want %t", x, got, want)
+				}
+				i, ok := s.FindGreaterThanOrEqual(x)
+				if wantOK := x <= 9; ok != wantOK {
+					t.Errorf("FindGreaterThanOrEqual(%d) returned %t, want %t", x, ok, wantOK)
+				} else if ok && s.At(i) != x|1 {
+					t.Errorf("FindGreaterThanOrEqual(%d) points to %d, want %d", x, s.At(i), x|1)
+				}
+			}
+			if got, ok := s.Min(); got != 1 || !ok {
+				t.Errorf("Min() = %d, %t, want 1, true", got, ok)
+			}
+			if got, ok := s.Max(); got != 9 || !ok {
+				t.Errorf("Max() = %d, %t, want 9, true", got, ok)
+			}
+		})
+	}
+}
+
+func TestSliceSetFuncKeepsFirst(t *testing.T) {
+	s := NewSliceSetFunc(compareRecords, record{2, "a"}, record{1, "b"}, record{2, "c"})
+	want := []record{{1, "b"}, {2, "a"}}
+	if diff := gcmp.Diff(want, slices.Collect(s.All()), gcmp.AllowUnexported(record{})); diff != "" {
+		t.Errorf("All() diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestSliceSetEmpty(t *testing.T) {
+	s := NewSet[string]().Freeze()
+	if _, ok := s.Min(); ok {
+		t.Errorf("Min() of an empty set returned true")
+	}
+	if _, ok := s.Max(); ok {
+		t.Errorf("Max() of an empty set returned true")
+	}
+	if _, ok := s.FindGreaterThanOrEqual(""); ok {
+		t.Errorf("FindGreaterThanOrEqual() in an empty set returned true")
+	}
+	if diff := gcmp.Diff([]string{}, slices.Collect(s.All()), cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("All() diff (-want +got):\n%s", diff)
+	}
+}
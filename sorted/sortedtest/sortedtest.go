@@ -0,0 +1,187 @@
+// Package sortedtest implements a conformance test suite for sorted set implementations.
+package sortedtest
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/mabu/algo/sorted"
+)
+
+// Universe bounds the elements used by the tests: they are all in [0, Universe).
+const Universe = 1000
+
+// Run tests an implementation of sorted.Ordered[int] ordered by <.
+// newSet must return a new empty set every time it is called.
+func Run(t *testing.T, newSet func() sorted.Ordered[int]) {
+	t.Run("Insert", func(t *testing.T) { testInsert(t, newSet) })
+	t.Run("Delete", func(t *testing.T) { testDelete(t, newSet) })
+	t.Run("Has", func(t *testing.T) { testHas(t, newSet) })
+	t.Run("AllMinMax", func(t *testing.T) { testAllMinMax(t, newSet) })
+	t.Run("Random", func(t *testing.T) { testRandom(t, newSet) })
+}
+
+func testInsert(t *testing.T, newSet func() sorted.Ordered[int]) {
+	s := newSet()
+	for i, op := range []struct {
+		x    int
+		want bool
+	}{
+		{1, true},
+		{1, false},
+		{2, true},
+		{3, true},
+		{2, false},
+		{3, false},
+	} {
+		if got := s.Insert(op.x); got != op.want {
+			t.Errorf("Operation #%d: Insert(%d) = %t, want %t", i, op.x, got, op.want)
+		}
+	}
+}
+
+func testDelete(t *testing.T, newSet func() sorted.Ordered[int]) {
+	for _, tc := range []struct {
+		insert  []int
+		delete  int
+		want    bool
+		wantAll []int
+	}{
+		{delete: 42},
+		{insert: []int{1}, delete: 2, wantAll: []int{1}},
+		{insert: []int{1}, delete: 1, want: true},
+		{insert: []int{1, 2}, delete: 1, want: true, wantAll: []int{2}},
+		{insert: []int{1, 2}, delete: 2, want: true, wantAll: []int{1}},
+		{insert: []int{1, 2}, delete: 3, wantAll: []int{1, 2}},
+		{insert: []int{2, 4, 6, 1, 7, 8}, delete: 3, wantAll: []int{1, 2, 4, 6, 7, 8}},
+		{insert: []int{2, 4, 6, 1, 7, 8}, delete: 1, want: true, wantAll: []int{2, 4, 6, 7, 8}},
+		{insert: []int{2, 4, 6, 1, 7, 8}, delete: 2, want: true, wantAll: []int{1, 4, 6, 7, 8}},
+		{insert: []int{2, 4, 6, 1, 7, 8}, delete: 4, want: true, wantAll: []int{1, 2, 6, 7, 8}},
+		{insert: []int{2, 4, 6, 1, 7, 8}, delete: 6, want: true, wantAll: []int{1, 2, 4, 7, 8}},
+		{insert: []int{2, 4, 6, 1, 7, 8}, delete: 7, want: true, wantAll: []int{1, 2, 4, 6, 8}},
+		{insert: []int{2, 4, 6, 1, 7, 8}, delete: 8, want: true, wantAll: []int{1, 2, 4, 6, 7}},
+		{insert: []int{2, 4, 6, 1, 7, 8}, delete: 9, wantAll: []int{1, 2, 4, 6, 7, 8}},
+	} {
+		s := newSet()
+		for _, in := range tc.insert {
+			s.Insert(in)
+		}
+		if got := s.Delete(tc.delete); got != tc.want {
+			t.Errorf("After inserting %v, s.Delete(%d) = %t, want %t", tc.insert, tc.delete, got, tc.want)
+		}
+		if diff := cmp.Diff(tc.wantAll, slices.Collect(s.All()), cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("After inserting %v and performing s.Delete(%d), All() diff (-want +got):\n%s", tc.insert, tc.delete, diff)
+		}
+		if got, want := s.Len(), len(tc.wantAll); got != want {
+			t.Errorf("After inserting %v and performing s.Delete(%d): Len() = %d, want %d", tc.insert, tc.delete, got, want)
+		}
+	}
+}
+
+func testHas(t *testing.T, newSet func() sorted.Ordered[int]) {
+	for _, tc := range []struct {
+		insert []int
+		delete []int
+		x      int
+		want   bool
+	}{
+		{x: 10},
+		{insert: []int{1}, x: 2},
+		{insert: []int{1, 2}, x: 2, want: true},
+		{insert: []int{1, 2}, x: 1, want: true},
+		{insert: []int{1, 2}, x: 3},
+		{insert: []int{1, 2}, x: 0},
+		{insert: []int{2, 3}, x: 1},
+		{insert: []int{1, 2}, delete: []int{1}, x: 2, want: true},
+		{insert: []int{1, 2}, delete: []int{1}, x: 1},
+	} {
+		s := newSet()
+		for _, in := range tc.insert {
+			s.Insert(in)
+		}
+		for _, de := range tc.delete {
+			s.Delete(de)
+		}
+		if got := s.Has(tc.x); got != tc.want {
+			t.Errorf("After inserting %v and deleting %v, s.Has(%d) = %t, want %t", tc.insert, tc.delete, tc.x, got, tc.want)
+		}
+	}
+}
+
+func testAllMinMax(t *testing.T, newSet func() sorted.Ordered[int]) {
+	for _, tc := range [][]int{
+		nil,
+		{1},
+		{1, 1},
+		{3, 2, 1},
+		{1, 2, 3, 2, 1},
+	} {
+		s := newSet()
+		for _, in := range tc {
+			s.Insert(in)
+		}
+		check(t, s, tc, "calling Insert with each of %v", tc)
+	}
+}
+
+func testRandom(t *testing.T, newSet func() sorted.Ordered[int]) {
+	r := rand.New(rand.NewPCG(1, 2))
+	s := newSet()
+	want := map[int]bool{}
+	for i := range 5000 {
+		x := r.IntN(Universe)
+		if r.IntN(5) < 3 {
+			if got := s.Insert(x); got != !want[x] {
+				t.Fatalf("Operation #%d: Insert(%d) = %t, want %t", i, x, got, !want[x])
+			}
+			want[x] = true
+		} else {
+			if got := s.Delete(x); got != want[x] {
+				t.Fatalf("Operation #%d: Delete(%d) = %t, want %t", i, x, got, want[x])
+			}
+			delete(want, x)
+		}
+		if got := s.Has(x); got != want[x] {
+			t.Fatalf("Operation #%d on %d: Has(%d) = %t, want %t", i, x, x, got, want[x])
+		}
+		if i%100 == 0 {
+			var in []int
+			for x := range want {
+				in = append(in, x)
+			}
+			check(t, s, in, "operation #%d", i)
+		}
+	}
+}
+
+// check verifies Len, All, Backward, Min and Max of s, which should contain the elements of in.
+// The remaining arguments describe how s was made.
+func check(t *testing.T, s sorted.Reader[int], in []int, format string, args ...any) {
+	t.Helper()
+	desc := fmt.Sprintf(format, args...)
+	want := slices.Compact(slices.Sorted(slices.Values(in)))
+	var wantMin, wantMax int
+	if len(want) > 0 {
+		wantMin, wantMax = want[0], want[len(want)-1]
+	}
+	if got := s.Len(); got != len(want) {
+		t.Errorf("After %s, Len() = %d, want %d", desc, got, len(want))
+	}
+	if diff := cmp.Diff(want, slices.Collect(s.All()), cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("After %s, All() diff (-want +got):\n%s", desc, diff)
+	}
+	slices.Reverse(want)
+	if diff := cmp.Diff(want, slices.Collect(s.Backward()), cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("After %s, Backward() diff (-want +got):\n%s", desc, diff)
+	}
+	if got, ok := s.Min(); got != wantMin || ok != (len(want) > 0) {
+		t.Errorf("After %s, Min() = %d, %t, want %d, %t", desc, got, ok, wantMin, len(want) > 0)
+	}
+	if got, ok := s.Max(); got != wantMax || ok != (len(want) > 0) {
+		t.Errorf("After %s, Max() = %d, %t, want %d, %t", desc, got, ok, wantMax, len(want) > 0)
+	}
+}
@@ -0,0 +1,67 @@
+package sorted
+
+import (
+	"iter"
+	"strings"
+)
+
+// The functions in this file work on sets of strings that are ordered byte-wise,
+// i.e. created by NewSet[string] or NewSetFunc(strings.Compare).
+
+// PrefixRange returns an iterator over the elements of s that start with prefix, in sorted order.
+func PrefixRange(s *Set[string], prefix string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		si, ok := s.FindGreaterThanOrEqual(prefix)
+		for ok && strings.HasPrefix(si.value, prefix) && yield(si.value) {
+			si, ok = si.Next()
+		}
+	}
+}
+
+// CountPrefix returns the number of elements of s that start with prefix.
+// Takes O(log n) time.
+func CountPrefix(s *Set[string], prefix string) int {
+	lo := s.prefix(prefix, false)
+	hi := s.size
+	// The smallest string that is greater than all strings with the prefix.
+	if end := strings.TrimRight(prefix, "\xff"); end != "" {
+		hi = s.prefix(end[:len(end)-1]+string([]byte{end[len(end)-1] + 1}), false)
+	}
+	return hi - lo
+}
+
+// LongestCommonPrefix returns the longest string that is a prefix of all elements of s.
+// Returns the empty string if s is empty.
+func LongestCommonPrefix(s *Set[string]) string {
+	lo, _ := s.Min()
+	hi, _ := s.Max()
+	return lo[:commonPrefixLen(lo, hi)]
+}
+
+// LongestPrefixOf returns the longest element of s that is a prefix of x, and true.
+// If there is no such element, returns false.
+func LongestPrefixOf(s *Set[string], x string) (string, bool) {
+	for {
+		n := s.prefix(x, true)
+		if n == 0 {
+			return "", false
+		}
+		// The largest element that is less than or equal to x.
+		p := s.at(n - 1).value
+		if strings.HasPrefix(x, p) {
+			return p, true
+		}
+		// Any prefix of x longer than the common part would be between p and x.
+		x = x[:commonPrefixLen(p, x)]
+	}
+}
+
+func commonPrefixLen(a, b string) int {
+	n := min(len(a), len(b))
+	for i := range n {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
@@ -0,0 +1,89 @@
+package sorted
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	gcmp "github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func newStringSet(elements ...string) map[string]*Set[string] {
+	sets := map[string]*Set[string]{
+		"NewSet":     NewSet[string](),
+		"NewSetFunc": NewSetFunc(strings.Compare),
+	}
+	for _, s := range sets {
+		for _, e := range elements {
+			s.Insert(e)
+		}
+	}
+	return sets
+}
+
+var words = []string{"", "a", "ab", "abc", "abd", "b", "ba", "b\xff", "b\xff\xff", "b\xff\xffa", "c"}
+
+func TestPrefixRange(t *testing.T) {
+	for name, s := range newStringSet(words...) {
+		t.Run(name, func(t *testing.T) {
+			for _, prefix := range []string{"", "a", "ab", "abc", "abe", "b", "b\xff", "b\xff\xff", "c", "d", "\xff"} {
+				var want []string
+				for _, w := range words {
+					if strings.HasPrefix(w, prefix) {
+						want = append(want, w)
+					}
+				}
+				if diff := gcmp.Diff(want, slices.Collect(PrefixRange(s, prefix)), cmpopts.EquateEmpty()); diff != "" {
+					t.Errorf("PrefixRange(%q) diff (-want +got):\n%s", prefix, diff)
+				}
+				if got := CountPrefix(s, prefix); got != len(want) {
+					t.Errorf("CountPrefix(%q) = %d, want %d", prefix, got, len(want))
+				}
+			}
+		})
+	}
+}
+
+func TestLongestCommonPrefix(t *testing.T) {
+	for _, tc := range []struct {
+		elements []string
+		want     string
+	}{
+		{},
+		{elements: []string{"abc"}, want: "abc"},
+		{elements: []string{"abc", "abd", "ab"}, want: "ab"},
+		{elements: []string{"abc", "b"}, want: ""},
+	} {
+		for name, s := range newStringSet(tc.elements...) {
+			if got := LongestCommonPrefix(s); got != tc.want {
+				t.Errorf("%s: LongestCommonPrefix(%q) = %q, want %q", name, tc.elements, got, tc.want)
+			}
+		}
+	}
+}
+
+func TestLongestPrefixOf(t *testing.T) {
+	for name, s := range newStringSet("/", "/usr", "/usr/local", "/var/lib", "/x") {
+		t.Run(name, func(t *testing.T) {
+			for _, tc := range []struct {
+				x      string
+				want   string
+				wantOK bool
+			}{
+				{x: "", wantOK: false},
+				{x: "/", want: "/", wantOK: true},
+				{x: "/usr/local/bin", want: "/usr/local", wantOK: true},
+				{x: "/usr/lib", want: "/usr", wantOK: true},
+				{x: "/var/log", want: "/", wantOK: true},
+				{x: "/w", want: "/", wantOK: true},
+				{x: "/xyz", want: "/x", wantOK: true},
+				{x: "usr", wantOK: false},
+			} {
+				if got, ok := LongestPrefixOf(s, tc.x); got != tc.want || ok != tc.wantOK {
+					t.Errorf("LongestPrefixOf(%q) = %q, %t, want %q, %t", tc.x, got, ok, tc.want, tc.wantOK)
+				}
+			}
+		})
+	}
+}
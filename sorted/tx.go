@@ -0,0 +1,99 @@
+package sorted
+
+// Tx is a transaction on a Set, which allows to undo the changes made since its beginning.
+//
+// Transactions are nested: beginning a transaction while another one is open
+// creates a savepoint within the outer transaction.
+// Ending a transaction also ends the open transactions nested in it:
+// Commit keeps their changes and Rollback reverts them.
+//
+// Every transaction must be ended. The set logs its changes while any transaction is open,
+// so an abandoned outermost transaction makes the log grow without bound.
+type Tx[T any] struct {
+	s     *Set[T]
+	outer *Tx[T] // The transaction that was open when this one began.
+	mark  int    // Length of the undo log at the beginning.
+	done  bool
+}
+
+type undoOp int8
+
+const (
+	undoInsert undoOp = iota
+	undoDelete
+	undoSetValue
+)
+
+// undo records an operation on a Set, and the value needed to reverse it.
+type undo[T any] struct {
+	op    undoOp
+	value T
+}
+
+// Begin starts a new transaction on the set.
+// While a transaction is open, the set records the changes to be able to reverse them,
+// which takes memory proportional to the number of changes.
+func (s *Set[T]) Begin() *Tx[T] {
+	s.tx = &Tx[T]{
+		s:     s,
+		outer: s.tx,
+		mark:  len(s.undo),
+	}
+	return s.tx
+}
+
+func (s *Set[T]) log(op undoOp, x T) {
+	if s.tx != nil {
+		s.undo = append(s.undo, undo[T]{op, x})
+	}
+}
+
+// Commit keeps the changes made in the transaction.
+// If this is a nested transaction, the changes can still be rolled back by the outer one.
+// Panics if the transaction has already ended.
+func (tx *Tx[T]) Commit() {
+	tx.end()
+	if tx.s.tx == nil {
+		tx.s.undo = nil
+	}
+}
+
+// Rollback reverts the set to the state it was in when the transaction began.
+// Takes O(k log n) time, where k is the number of changes made in the transaction.
+//
+// Items that were obtained during the transaction or deleted in it
+// may no longer refer to elements of the set.
+// Panics if the transaction has already ended.
+func (tx *Tx[T]) Rollback() {
+	tx.end()
+	s := tx.s
+	log := s.undo[tx.mark:]
+	s.undo = s.undo[:tx.mark]
+	s.tx = nil // Do not log the reversals.
+	for i := len(log) - 1; i >= 0; i-- {
+		switch u := log[i]; u.op {
+		case undoInsert:
+			s.Delete(u.value)
+		case undoDelete:
+			s.Insert(u.value)
+		case undoSetValue:
+			s.Replace(u.value)
+		}
+	}
+	s.tx = tx.outer
+	if s.tx == nil {
+		s.undo = nil
+	}
+}
+
+func (tx *Tx[T]) end() {
+	if tx.done {
+		panic("sorted: transaction has already ended")
+	}
+	// The transaction has not ended, so it is on the chain from the innermost one.
+	for t := tx.s.tx; t != tx; t = t.outer {
+		t.done = true
+	}
+	tx.done = true
+	tx.s.tx = tx.outer
+}
@@ -0,0 +1,120 @@
+package sorted
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	gcmp "github.com/google/go-cmp/cmp"
+)
+
+func TestTxRollback(t *testing.T) {
+	s := NewSetFunc(compareRecords)
+	s.Insert(record{1, "a"})
+	s.Insert(record{2, "b"})
+	tx := s.Begin()
+	s.Insert(record{3, "c"})
+	s.Delete(record{1, ""})
+	s.Replace(record{2, "x"})
+	si, _ := s.GetOrInsert(record{3, ""})
+	si.SetValue(record{3, "y"})
+	s.Insert(record{1, "z"})
+	tx.Rollback()
+	want := []record{{1, "a"}, {2, "b"}}
+	if diff := gcmp.Diff(want, slices.Collect(s.All()), gcmp.AllowUnexported(record{})); diff != "" {
+		t.Errorf("After Rollback(), All() diff (-want +got):\n%s", diff)
+	}
+	if len(s.undo) != 0 {
+		t.Errorf("After Rollback() of the outermost transaction, undo log has %d entries, want 0", len(s.undo))
+	}
+}
+
+func TestTxNested(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	s := NewSet[int]()
+	var txs []*Tx[int]
+	var states [][]int
+	for op := range 3000 {
+		switch r.IntN(10) {
+		case 0:
+			txs = append(txs, s.Begin())
+			states = append(states, slices.Collect(s.All()))
+		case 1, 2:
+			if len(txs) == 0 {
+				continue
+			}
+			tx := txs[len(txs)-1]
+			want := states[len(states)-1]
+			txs, states = txs[:len(txs)-1], states[:len(states)-1]
+			if r.IntN(2) == 0 {
+				tx.Commit()
+				continue
+			}
+			tx.Rollback()
+			if diff := gcmp.Diff(want, slices.Collect(s.All())); diff != "" {
+				t.Fatalf("Operation #%d: after Rollback(), All() diff (-want +got):\n%s", op, diff)
+			}
+			checkTree(t, &s.tree)
+		case 3, 4, 5:
+			s.Delete(r.IntN(50))
+		default:
+			s.Insert(r.IntN(50))
+		}
+	}
+}
+
+func TestTxAbandoned(t *testing.T) {
+	s := NewSet[int]()
+	s.Insert(1)
+	outer := s.Begin()
+	s.Insert(2)
+	s.Begin()
+	s.Insert(3)
+	s.Begin() // Abandoned.
+	s.Delete(1)
+	outer.Rollback()
+	if got, want := slices.Collect(s.All()), []int{1}; !slices.Equal(got, want) {
+		t.Errorf("After Rollback() of the outer transaction, All() = %v, want %v", got, want)
+	}
+	outer = s.Begin()
+	s.Begin() // Abandoned.
+	s.Insert(4)
+	outer.Commit()
+	if got, want := slices.Collect(s.All()), []int{1, 4}; !slices.Equal(got, want) {
+		t.Errorf("After Commit() of the outer transaction, All() = %v, want %v", got, want)
+	}
+	if s.tx != nil || len(s.undo) != 0 {
+		t.Errorf("After Commit() of the outermost transaction, the set still logs %d changes", len(s.undo))
+	}
+}
+
+func TestTxMisuse(t *testing.T) {
+	for name, f := range map[string]func(s *Set[int]){
+		"CommitTwice": func(s *Set[int]) {
+			tx := s.Begin()
+			tx.Commit()
+			tx.Commit()
+		},
+		"RollbackAfterCommit": func(s *Set[int]) {
+			tx := s.Begin()
+			tx.Commit()
+			tx.Rollback()
+		},
+		"InnerAfterOuter": func(s *Set[int]) {
+			tx := s.Begin()
+			inner := s.Begin()
+			tx.Rollback()
+			s.Begin()
+			inner.Commit()
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			f(NewSet[int]())
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package sorted
+
+import (
+	"cmp"
+	"math"
+)
+
+// Window keeps the last samples of a stream and answers order statistic queries about them,
+// such as running median or percentiles, in O(log n) time.
+// Unlike Set, it may contain samples that compare as equal.
+type Window[T any] struct {
+	s      *Set[sample[T]]
+	queue  []sample[T] // Samples in the order of arrival.
+	next   uint64      // Sequence number of the next sample.
+	length int
+}
+
+// sample is a value with a sequence number that distinguishes equal values.
+type sample[T any] struct {
+	value T
+	seq   uint64
+}
+
+// NewWindow creates a new Window that keeps the last length samples, using < for comparisons.
+// Panics if length is not positive.
+func NewWindow[T cmp.Ordered](length int) *Window[T] {
+	return NewWindowFunc(length, cmp.Compare[T])
+}
+
+// NewWindowFunc creates a new Window that keeps the last length samples, ordered according to cmp.
+// Panics if length is not positive.
+func NewWindowFunc[T any](length int, cmp func(T, T) int) *Window[T] {
+	if length <= 0 {
+		panic("sorted: Window length must be positive")
+	}
+	return &Window[T]{
+		s: NewSetFunc(func(a, b sample[T]) int {
+			if c := cmp(a.value, b.value); c != 0 {
+				return c
+			}
+			return compareSeq(a.seq, b.seq)
+		}),
+		length: length,
+	}
+}
+
+func compareSeq(a, b uint64) int {
+	return cmp.Compare(a, b)
+}
+
+// Len returns the number of samples in the window.
+func (w *Window[T]) Len() int {
+	return w.s.Len()
+}
+
+// Push adds x to the window.
+// If the window was full, evicts the oldest sample and returns it and true.
+// Otherwise returns false.
+func (w *Window[T]) Push(x T) (evicted T, ok bool) {
+	if w.s.Len() == w.length {
+		evicted, ok = w.Evict()
+	}
+	s := sample[T]{x, w.next}
+	w.next++
+	w.s.Insert(s)
+	w.queue = append(w.queue, s)
+	return evicted, ok
+}
+
+// Evict removes the oldest sample from the window and returns it and true.
+// If the window is empty returns false.
+func (w *Window[T]) Evict() (T, bool) {
+	if len(w.queue) == 0 {
+		var x T
+		return x, false
+	}
+	s := w.queue[0]
+	w.queue = w.queue[1:]
+	w.s.Delete(s)
+	return s.value, true
+}
+
+// Quantile returns the q-quantile of the samples in the window and true,
+// using the nearest-rank method: the smallest sample
+// that is greater than or equal to at least q of all samples.
+// If the window is empty returns false.
+// Panics if q is not between 0 and 1.
+func (w *Window[T]) Quantile(q float64) (T, bool) {
+	if !(q >= 0 && q <= 1) {
+		panic("sorted: quantile out of range")
+	}
+	n := w.s.Len()
+	if n == 0 {
+		var x T
+		return x, false
+	}
+	i := int(math.Ceil(q*float64(n))) - 1
+	return w.s.at(min(max(i, 0), n-1)).value.value, true
+}
+
+// Median returns the median of the samples in the window and true.
+// If the number of samples is even, returns the lower one of the two middle samples.
+// If the window is empty returns false.
+func (w *Window[T]) Median() (T, bool) {
+	return w.Quantile(0.5)
+}
@@ -0,0 +1,73 @@
+package sorted
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestWindow(t *testing.T) {
+	r := rand.New(rand.NewPCG(11, 12))
+	const length = 50
+	w := NewWindow[int](length)
+	var samples []int
+	for i := range 1000 {
+		x := r.IntN(20) // Many duplicates.
+		evicted, ok := w.Push(x)
+		samples = append(samples, x)
+		if len(samples) > length {
+			if !ok || evicted != samples[0] {
+				t.Fatalf("Push #%d evicted %d, %t, want %d, true", i, evicted, ok, samples[0])
+			}
+			samples = samples[1:]
+		} else if ok {
+			t.Fatalf("Push #%d evicted %d from a window that was not full", i, evicted)
+		}
+		if r.IntN(10) == 0 {
+			got, ok := w.Evict()
+			if !ok || got != samples[0] {
+				t.Fatalf("After push #%d, Evict() = %d, %t, want %d, true", i, got, ok, samples[0])
+			}
+			samples = samples[1:]
+		}
+		if got, want := w.Len(), len(samples); got != want {
+			t.Fatalf("After push #%d, Len() = %d, want %d", i, got, want)
+		}
+		sorted := slices.Sorted(slices.Values(samples))
+		if len(sorted) == 0 {
+			continue
+		}
+		for _, tc := range []struct {
+			q    float64
+			want int
+		}{
+			{0, sorted[0]},
+			{0.5, sorted[(len(sorted)-1)/2]},
+			{0.99, sorted[(99*len(sorted)+99)/100-1]},
+			{1, sorted[len(sorted)-1]},
+		} {
+			if got, ok := w.Quantile(tc.q); !ok || got != tc.want {
+				t.Fatalf("After push #%d, Quantile(%v) = %d, %t, want %d, true", i, tc.q, got, ok, tc.want)
+			}
+		}
+		if got, _ := w.Median(); got != sorted[(len(sorted)-1)/2] {
+			t.Fatalf("After push #%d, Median() = %d, want %d", i, got, sorted[(len(sorted)-1)/2])
+		}
+	}
+}
+
+func TestWindowEmpty(t *testing.T) {
+	w := NewWindow[float64](3)
+	if _, ok := w.Median(); ok {
+		t.Errorf("Median() of an empty window returned true")
+	}
+	if _, ok := w.Evict(); ok {
+		t.Errorf("Evict() from an empty window returned true")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Quantile(1.5) did not panic")
+		}
+	}()
+	w.Quantile(1.5)
+}